@@ -0,0 +1,64 @@
+package enviper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BindFieldEnv binds the env variable to the field of rawVal located by its index path,
+// just like reflect.Type.FieldByIndex does, e.g. `[]int{1, 0}` is the first field of the second field.
+// The config key of the field is derived from tags, so it's a low-level alternative to viper.BindEnv
+// for tools that target fields programmatically. The env variable is used as is, without env prefix.
+func (e *Enviper) BindFieldEnv(rawVal interface{}, fieldIndex []int, envName string) error {
+	if envName == "" {
+		return errors.New("env name is empty")
+	}
+	t := reflect.TypeOf(rawVal)
+	var path []string
+	for _, i := range fieldIndex {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return fmt.Errorf("can't bind field %v: %v is not a struct", fieldIndex, t)
+		}
+		if i < 0 || i >= t.NumField() {
+			return fmt.Errorf("can't bind field %v: %s has no field with index %d", fieldIndex, t, i)
+		}
+		sf := t.Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" {
+			return fmt.Errorf("can't bind field %v: %s.%s is ignored", fieldIndex, t, sf.Name)
+		}
+		if !e.isSquashed(sf, name, opts) {
+			if name == "" {
+				name = sf.Name
+			}
+			path = append(path, name)
+		}
+		t = sf.Type
+	}
+	if len(path) == 0 {
+		return fmt.Errorf("can't bind field %v: it has no config key", fieldIndex)
+	}
+
+	key := strings.ToLower(strings.Join(path, "."))
+	if e.boundEnvs == nil {
+		e.boundEnvs = map[string]string{}
+	}
+	e.boundEnvs[key] = envName
+	return e.Viper.BindEnv(key, envName)
+}
+
+// fieldEnvName returns the name of env variable bound to the field
+func (e *Enviper) fieldEnvName(f field) string {
+	if env, ok := e.boundEnvs[strings.ToLower(strings.Join(f.path, "."))]; ok {
+		return env
+	}
+	if len(f.envNames) > 0 {
+		return e.firstSetEnv(f.envNames)
+	}
+	return e.envName(f.env)
+}
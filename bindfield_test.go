@@ -0,0 +1,50 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindFieldEnv(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"LEGACY_BAZ":   "42",
+		"LEGACY_QUUUX": "true",
+		"APP_BAR_BAZ":  "1",
+		"APP_FOO":      "foo",
+	})()
+
+	var c Config
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	// Config.Bar.BAZ
+	assert.Nil(t, e.BindFieldEnv(&c, []int{2, 0}, "LEGACY_BAZ"))
+	// Config.QUX.Quuux, QUX is squashed
+	assert.Nil(t, e.BindFieldEnv(&c, []int{5, 0}, "LEGACY_QUUUX"))
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, 42, c.Bar.BAZ)
+	assert.True(t, c.Quuux)
+	assert.Equal(t, "foo", c.Foo)
+	assert.Equal(t, []string{"APP_FOO", "LEGACY_BAZ", "LEGACY_QUUUX"}, e.ConsumedEnvVars(&c))
+}
+
+func TestBindFieldEnvErrors(t *testing.T) {
+	var c Config
+	e := enviper.New(viper.New())
+
+	for message, index := range map[string][]int{
+		"can't bind field [42]: enviper_test.Config has no field with index 42": {42},
+		"can't bind field [0 0]: string is not a struct":                        {0, 0},
+		"can't bind field [9]: enviper_test.Config.TagValueWithDash is ignored": {9},
+		"can't bind field [5]: it has no config key":                            {5},
+	} {
+		err := e.BindFieldEnv(&c, index, "ENV")
+		if assert.NotNil(t, err, message) {
+			assert.Equal(t, message, err.Error())
+		}
+	}
+	assert.EqualError(t, e.BindFieldEnv(&c, []int{0}, ""), "env name is empty")
+}
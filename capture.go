@@ -0,0 +1,77 @@
+package enviper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prefixCapture is the env prefix captured to a map field
+type prefixCapture struct {
+	prefix       string
+	preserveCase bool
+}
+
+// WithPrefixCapture collects every env variable starting with the prefix to the map field by its config key,
+// e.g. `e.WithPrefixCapture("extra", "APP_EXTRA")` sets the `foo_bar` entry of `Extra map[string]string`
+// from `APP_EXTRA_FOO_BAR`. Keys are the rest of env variable names after the prefix lower cased,
+// entries of config file are kept unless they are overridden. The prefix is used as is, without env prefix.
+func (e *Enviper) WithPrefixCapture(path, envPrefix string) *Enviper {
+	return e.addPrefixCapture(path, prefixCapture{prefix: envPrefix})
+}
+
+// WithPrefixCapturePreservingCase does the same as WithPrefixCapture, but keeps keys in the case of env variables,
+// so `APP_EXTRA_FOO_BAR` sets the `FOO_BAR` entry.
+func (e *Enviper) WithPrefixCapturePreservingCase(path, envPrefix string) *Enviper {
+	return e.addPrefixCapture(path, prefixCapture{prefix: envPrefix, preserveCase: true})
+}
+
+func (e *Enviper) addPrefixCapture(path string, c prefixCapture) *Enviper {
+	if e.prefixCaptures == nil {
+		e.prefixCaptures = map[string]prefixCapture{}
+	}
+	c.prefix = strings.TrimSuffix(c.prefix, e.separator()) + e.separator()
+	e.prefixCaptures[strings.ToLower(path)] = c
+	return e
+}
+
+// readPrefixCaptures collects env variables of captured prefixes to overrides
+func (e *Enviper) readPrefixCaptures() {
+	if len(e.prefixCaptures) == 0 {
+		return
+	}
+	environ := e.environ()
+	for path, c := range e.prefixCaptures {
+		captured := map[string]interface{}{}
+		for _, kv := range environ {
+			i := strings.Index(kv, "=")
+			if i == -1 || !strings.HasPrefix(kv[:i], c.prefix) || len(kv[:i]) == len(c.prefix) {
+				continue
+			}
+			key := kv[len(c.prefix):i]
+			if !c.preserveCase {
+				key = strings.ToLower(key)
+			}
+			if _, ok := captured[key]; !ok {
+				captured[key] = kv[i+1:]
+			}
+		}
+		if len(captured) == 0 {
+			continue
+		}
+		m := map[string]interface{}{}
+		switch file := e.Viper.Get(path).(type) {
+		case map[string]interface{}:
+			for k, v := range file {
+				m[k] = v
+			}
+		case map[interface{}]interface{}:
+			for k, v := range file {
+				m[fmt.Sprint(k)] = v
+			}
+		}
+		for k, v := range captured {
+			m[k] = v
+		}
+		e.overrides[path] = m
+	}
+}
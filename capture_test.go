@@ -0,0 +1,79 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type CaptureConfig struct {
+	Name  string
+	Extra map[string]string
+}
+
+func TestPrefixCapture(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+name: app
+extra:
+  region: eu
+  zone: a
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_EXTRA_ZONE":      "b",
+		"APP_EXTRA_TEAM_NAME": "core",
+		"APP_EXTRA_OWNER":     "ops",
+		"APP_EXTRAS":          "ignored",
+		"APP_EXTRA_":          "ignored",
+		"APP_NAME":            "svc",
+	})()
+
+	var c CaptureConfig
+	e := enviper.New(viper.New()).WithPrefixCapture("extra", "APP_EXTRA")
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "svc", c.Name)
+		assert.Equal(t, map[string]string{
+			"region":    "eu",
+			"zone":      "b",
+			"team_name": "core",
+			"owner":     "ops",
+		}, c.Extra)
+	}
+}
+
+func TestPrefixCapturePreservingCase(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"LABEL_Team":     "core",
+		"LABEL_COST_CTR": "42",
+	})()
+
+	var c CaptureConfig
+	e := enviper.New(viper.New()).WithPrefixCapturePreservingCase("extra", "LABEL_")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, map[string]string{"Team": "core", "COST_CTR": "42"}, c.Extra)
+	}
+}
+
+func TestPrefixCaptureNotSet(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+extra:
+  region: eu
+`)
+	defer cleanup()
+
+	var c CaptureConfig
+	e := enviper.New(viper.New()).WithPrefixCapture("extra", "APP_EXTRA")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, map[string]string{"region": "eu"}, c.Extra)
+	}
+}
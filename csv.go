@@ -0,0 +1,54 @@
+package enviper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithCSVSlices makes `[][]string` fields and slices of structs accept CSV (RFC 4180) values,
+// e.g. `MYAPP_ROWS=$'a,b\nc,d'` is `[][]string{{"a", "b"}, {"c", "d"}}`.
+// For slices of structs the first row is the header with keys of fields, each other row is an element.
+// Quoted values could contain commas, quotes and newlines. JSON arrays are still accepted.
+func (e *Enviper) WithCSVSlices() *Enviper {
+	e.csvSlices = true
+	return e
+}
+
+// csvHook parses CSV values of `[][]string` fields and slices of structs
+func (e *Enviper) csvHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Slice {
+		return data, nil
+	}
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	table := et.Kind() == reflect.Slice && et.Elem().Kind() == reflect.String
+	if !table && (et.Kind() != reflect.Struct || e.isLeaf(et)) {
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	if raw == "" || strings.HasPrefix(raw, "[") || !table && strings.HasPrefix(raw, "{") {
+		return data, nil
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(raw)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q as CSV: %s", raw, err)
+	}
+	if table {
+		return rows, nil
+	}
+	header := rows[0]
+	list := make([]interface{}, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		elem := make(map[string]interface{}, len(header))
+		for i, key := range header {
+			elem[strings.TrimSpace(key)] = row[i]
+		}
+		list = append(list, elem)
+	}
+	return list, nil
+}
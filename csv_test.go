@@ -0,0 +1,94 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type CSVConfig struct {
+	Rows  [][]string
+	Users []struct {
+		Name  string
+		Age   int
+		Notes string
+	}
+	Tags []string
+}
+
+func TestCSVSlices(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_ROWS":  "a,b\n\"c,d\",\"say \"\"hi\"\"\"\n\"multi\nline\",e",
+		"APP_USERS": "name,age,notes\nalice,30,\"likes, commas\"\nbob,25,",
+		"APP_TAGS":  "x,y",
+	})()
+
+	var c CSVConfig
+	e := enviper.New(viper.New()).WithCSVSlices()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c,d", `say "hi"`}, {"multi\nline", "e"}}, c.Rows)
+	if assert.Len(t, c.Users, 2) {
+		assert.Equal(t, "alice", c.Users[0].Name)
+		assert.Equal(t, 30, c.Users[0].Age)
+		assert.Equal(t, "likes, commas", c.Users[0].Notes)
+		assert.Equal(t, "bob", c.Users[1].Name)
+		assert.Equal(t, 25, c.Users[1].Age)
+	}
+	assert.Equal(t, []string{"x", "y"}, c.Tags)
+}
+
+func TestCSVSlicesKeepJSON(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_ROWS":  `[["a","b"],["c"]]`,
+		"APP_USERS": `[{"name":"alice"}]`,
+	})()
+
+	var c CSVConfig
+	e := enviper.New(viper.New()).WithCSVSlices()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, c.Rows)
+	if assert.Len(t, c.Users, 1) {
+		assert.Equal(t, "alice", c.Users[0].Name)
+	}
+}
+
+func TestCSVAndKVSlicesKeepJSONObjects(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_USERS":   `{"name":"alice","age":30} {"name":"bob"}`,
+		"APP_HEADERS": `{"key":"Accept","value":"text/html"} {"key":"X-Empty"}`,
+	})()
+
+	var c struct {
+		CSVConfig `mapstructure:",squash"`
+		Headers   []Header
+	}
+	e := enviper.New(viper.New()).WithCSVSlices().WithKVStructSlices("Name", "Value")
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	if assert.Len(t, c.Users, 2) {
+		assert.Equal(t, "alice", c.Users[0].Name)
+		assert.Equal(t, 30, c.Users[0].Age)
+		assert.Equal(t, "bob", c.Users[1].Name)
+	}
+	assert.Equal(t, []Header{{Name: "Accept", Value: "text/html"}, {Name: "X-Empty"}}, c.Headers)
+}
+
+func TestCSVSlicesInvalid(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_ROWS": "a,b\nc"})()
+
+	var c CSVConfig
+	e := enviper.New(viper.New()).WithCSVSlices()
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't parse \"a,b\\nc\" as CSV")
+	}
+}
@@ -0,0 +1,38 @@
+package enviper
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// WithDecimalComma makes numeric fields accept numbers formatted with comma as decimal separator
+// and dots or spaces as thousands separators, e.g. `1,5` or `1.234.567,89`.
+// Numbers without thousands separators like `1.5` are still parsed as usual,
+// while `1.500` is one thousand five hundred. Strings are split to slices before, so use it with
+// WithRecordSeparators or JSON arrays for slices of numbers.
+func (e *Enviper) WithDecimalComma() *Enviper {
+	e.decimalComma = true
+	return e
+}
+
+var decimalCommaNumber = regexp.MustCompile(`^[+-]?(?:\d{1,3}(?:[. ]\d{3})+|\d+)(?:,\d+)?$`)
+
+// decimalCommaHook converts numbers with decimal comma to the C-locale format and leaves other strings as is
+func decimalCommaHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t == durationType {
+		return data, nil
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	if !decimalCommaNumber.MatchString(raw) {
+		return data, nil
+	}
+	return strings.NewReplacer(".", "", " ", "", ",", ".").Replace(raw), nil
+}
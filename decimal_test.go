@@ -0,0 +1,66 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type DecimalConfig struct {
+	Rate  float64
+	Count int
+	Limit uint32
+}
+
+func TestDecimalComma(t *testing.T) {
+	for _, tc := range []struct {
+		rate, count, limit string
+		expected           DecimalConfig
+	}{
+		{"1,5", "10", "7", DecimalConfig{Rate: 1.5, Count: 10, Limit: 7}},
+		{"-0,25", "-3", "0", DecimalConfig{Rate: -0.25, Count: -3}},
+		{"1.234.567,89", "1.000", "65 536", DecimalConfig{Rate: 1234567.89, Count: 1000, Limit: 65536}},
+		{"1 234,5", "+12 345", "4.294.967.295", DecimalConfig{Rate: 1234.5, Count: 12345, Limit: 4294967295}},
+		{"2.5", "42", "1", DecimalConfig{Rate: 2.5, Count: 42, Limit: 1}},
+		{"1e3", "0", "0", DecimalConfig{Rate: 1000}},
+	} {
+		func() {
+			defer setenv(t, map[string]string{"APP_RATE": tc.rate, "APP_COUNT": tc.count, "APP_LIMIT": tc.limit})()
+
+			var c DecimalConfig
+			e := enviper.New(viper.New()).WithDecimalComma()
+			e.SetEnvPrefix("APP")
+			if assert.Nil(t, e.Unmarshal(&c), tc.rate) {
+				assert.Equal(t, tc.expected, c)
+			}
+		}()
+	}
+}
+
+func TestDecimalCommaInvalid(t *testing.T) {
+	for _, env := range []map[string]string{
+		{"APP_COUNT": "1,5"},
+		{"APP_RATE": "1.23,4"},
+		{"APP_RATE": "1,2,3"},
+	} {
+		func() {
+			defer setenv(t, env)()
+
+			var c DecimalConfig
+			e := enviper.New(viper.New()).WithDecimalComma()
+			e.SetEnvPrefix("APP")
+			assert.NotNil(t, e.Unmarshal(&c), env)
+		}()
+	}
+}
+
+func TestDecimalCommaDisabled(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_RATE": "1,5"})()
+
+	var c DecimalConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	assert.NotNil(t, e.Unmarshal(&c))
+}
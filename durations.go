@@ -0,0 +1,183 @@
+package enviper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// WithISO8601Durations makes time.Duration fields accept ISO8601 durations like `PT1H30M` or `P1DT12H`
+// besides Go durations like `1h30m`. Years and months are rejected as their length is ambiguous,
+// days are 24 hours and weeks are 7 days.
+func (e *Enviper) WithISO8601Durations() *Enviper {
+	e.iso8601Durations = true
+	return e
+}
+
+// WithDurationAliases registers custom units of time.Duration fields, e.g. `min` or `hr`:
+//
+//	e.WithDurationAliases(map[string]time.Duration{"min": time.Minute, "hr": time.Hour})
+//
+// Custom units could be mixed with Go units like `2hr30m`, units are case sensitive.
+func (e *Enviper) WithDurationAliases(aliases map[string]time.Duration) *Enviper {
+	if e.durationAliases == nil {
+		e.durationAliases = map[string]time.Duration{}
+	}
+	for unit, d := range aliases {
+		e.durationAliases[unit] = d
+	}
+	return e
+}
+
+var durationPart = regexp.MustCompile(`(\d+(?:\.\d*)?|\.\d+)([^\d.]+)`)
+
+// durationAliasesHook parses durations with custom units and leaves other strings to the default duration hook
+func (e *Enviper) durationAliasesHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != durationType {
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	unsigned := strings.TrimLeft(raw, "+-")
+	if len(raw)-len(unsigned) > 1 {
+		return data, nil
+	}
+
+	var d time.Duration
+	aliased, end := false, 0
+	for _, m := range durationPart.FindAllStringSubmatchIndex(unsigned, -1) {
+		if m[0] != end {
+			return data, nil
+		}
+		end = m[1]
+		part, unit := unsigned[m[0]:m[1]], unsigned[m[4]:m[5]]
+		if alias, ok := e.durationAliases[unit]; ok {
+			n, err := strconv.ParseFloat(unsigned[m[2]:m[3]], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration %q: %s", raw, err)
+			}
+			d += time.Duration(n * float64(alias))
+			aliased = true
+			continue
+		}
+		pd, err := time.ParseDuration(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %s", raw, err)
+		}
+		d += pd
+	}
+	if !aliased || end != len(unsigned) {
+		return data, nil
+	}
+	if strings.HasPrefix(raw, "-") {
+		d = -d
+	}
+	return d, nil
+}
+
+var iso8601Duration = regexp.MustCompile(`^([-+])?P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// iso8601DurationHook parses ISO8601 durations and leaves other strings to the default duration hook
+func iso8601DurationHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != durationType {
+		return data, nil
+	}
+	raw := strings.ToUpper(strings.TrimSpace(reflect.ValueOf(data).String()))
+	if !strings.HasPrefix(strings.TrimLeft(raw, "+-"), "P") {
+		return data, nil
+	}
+	return parseISO8601Duration(raw)
+}
+
+func parseISO8601Duration(raw string) (time.Duration, error) {
+	m := iso8601Duration.FindStringSubmatch(raw)
+	if m == nil || strings.HasSuffix(raw, "T") || strings.TrimLeft(raw, "+-") == "P" {
+		return 0, fmt.Errorf("invalid ISO8601 duration %q", raw)
+	}
+	if m[2] != "" || m[3] != "" {
+		return 0, fmt.Errorf("ISO8601 duration %q has years or months, that have no fixed length", raw)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		part := m[i+4]
+		if part == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.Replace(part, ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO8601 duration %q: %s", raw, err)
+		}
+		d += time.Duration(n * float64(unit))
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// WithClockDurations makes time.Duration fields accept clock durations like `01:30:00` (HH:MM:SS) or `05:30` (MM:SS)
+// besides Go durations like `1h30m`. Seconds could have a fraction (e.g. `00:00:01.5`),
+// minutes and seconds following the leading number must be less than 60.
+func (e *Enviper) WithClockDurations() *Enviper {
+	e.clockDurations = true
+	return e
+}
+
+var clockDuration = regexp.MustCompile(`^([-+])?(\d+):(\d+)(?::(\d+))?(\.\d+)?$`)
+
+// clockDurationHook parses clock durations and leaves other strings to the default duration hook
+func clockDurationHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != durationType {
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	if !strings.Contains(raw, ":") {
+		return data, nil
+	}
+	return parseClockDuration(raw)
+}
+
+func parseClockDuration(raw string) (time.Duration, error) {
+	m := clockDuration.FindStringSubmatch(raw)
+	if m == nil {
+		return 0, fmt.Errorf("invalid clock duration %q", raw)
+	}
+	parts := []string{"0", m[2], m[3]}
+	if m[4] != "" {
+		parts = []string{m[2], m[3], m[4]}
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		name := []string{"hours", "minutes", "seconds"}[i]
+		n, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil || n > int64(math.MaxInt64/unit) {
+			return 0, fmt.Errorf("clock duration %q is out of range", raw)
+		}
+		// the leading number isn't limited, so `90:00` is 90 minutes
+		if n >= 60 && (unit == time.Second || unit == time.Minute && m[4] != "") {
+			return 0, fmt.Errorf("clock duration %q has %d %s, that must be less than 60", raw, n, name)
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[5] != "" {
+		frac, err := strconv.ParseFloat("0"+m[5], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid clock duration %q: %s", raw, err)
+		}
+		d += time.Duration(frac * float64(time.Second))
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("clock duration %q is out of range", raw)
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,237 @@
+package enviper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func unmarshalDuration(t *testing.T, e *enviper.Enviper, raw string) (time.Duration, error) {
+	defer setenv(t, map[string]string{"APP_TIMEOUT": raw})()
+
+	var c struct {
+		Timeout time.Duration
+	}
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	return c.Timeout, err
+}
+
+func TestISO8601Durations(t *testing.T) {
+	for raw, expected := range map[string]time.Duration{
+		"PT1H30M":   90 * time.Minute,
+		"pt15s":     15 * time.Second,
+		"PT0.5S":    500 * time.Millisecond,
+		"PT1,5M":    90 * time.Second,
+		"P1DT12H":   36 * time.Hour,
+		"P2W":       14 * 24 * time.Hour,
+		"-PT10M":    -10 * time.Minute,
+		"1h30m":     90 * time.Minute,
+		"250ms":     250 * time.Millisecond,
+		"-1h2m3.5s": -(time.Hour + 2*time.Minute + 3500*time.Millisecond),
+	} {
+		d, err := unmarshalDuration(t, enviper.New(viper.New()).WithISO8601Durations(), raw)
+		if assert.Nil(t, err, raw) {
+			assert.Equal(t, expected, d, raw)
+		}
+	}
+}
+
+func TestISO8601DurationsInvalid(t *testing.T) {
+	for raw, message := range map[string]string{
+		"P":      `invalid ISO8601 duration "P"`,
+		"PT":     `invalid ISO8601 duration "PT"`,
+		"P1H":    `invalid ISO8601 duration "P1H"`,
+		"PT1H1D": `invalid ISO8601 duration "PT1H1D"`,
+		"P1Y":    `ISO8601 duration "P1Y" has years or months`,
+		"P1M":    `ISO8601 duration "P1M" has years or months`,
+		"1 hour": `time: unknown unit`,
+		"PTwoS":  `invalid ISO8601 duration "PTWOS"`,
+	} {
+		_, err := unmarshalDuration(t, enviper.New(viper.New()).WithISO8601Durations(), raw)
+		if assert.NotNil(t, err, raw) {
+			assert.Contains(t, err.Error(), message, raw)
+		}
+	}
+}
+
+func TestISO8601DurationsDisabled(t *testing.T) {
+	_, err := unmarshalDuration(t, enviper.New(viper.New()), "PT1H")
+	assert.NotNil(t, err)
+}
+
+func TestMapOfDurations(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+timeouts:
+  read: 1s
+  idle: 1m
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_TIMEOUTS_READ":           "5s",
+		"APP_TIMEOUTS_WRITE":          "10s",
+		"APP_TIMEOUTS_GRACEFUL_CLOSE": "PT1M30S",
+	})()
+
+	var c struct {
+		Timeouts map[string]time.Duration
+	}
+	e := enviper.New(viper.New()).WithISO8601Durations()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, map[string]time.Duration{
+		"read":           5 * time.Second,
+		"write":          10 * time.Second,
+		"idle":           time.Minute,
+		"graceful_close": 90 * time.Second,
+	}, c.Timeouts)
+}
+
+func TestMapOfDurationsInvalid(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_TIMEOUTS_READ": "soon"})()
+
+	var c struct {
+		Timeouts map[string]time.Duration
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `invalid duration`)
+	}
+}
+
+func TestPointersToDurationAndTime(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TIMEOUT":       "1m30s",
+		"APP_DEADLINE":      "2020-01-02T03:04:05Z",
+		"APP_RETRY_BACKOFF": "PT5S",
+	})()
+
+	var c struct {
+		Timeout  *time.Duration
+		Deadline *time.Time
+		Idle     *time.Duration
+		Expires  *time.Time
+		Retry    struct {
+			Backoff *time.Duration
+		}
+	}
+	e := enviper.New(viper.New()).WithISO8601Durations()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	if assert.NotNil(t, c.Timeout) {
+		assert.Equal(t, 90*time.Second, *c.Timeout)
+	}
+	if assert.NotNil(t, c.Deadline) {
+		assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), *c.Deadline)
+	}
+	if assert.NotNil(t, c.Retry.Backoff) {
+		assert.Equal(t, 5*time.Second, *c.Retry.Backoff)
+	}
+	assert.Nil(t, c.Idle)
+	assert.Nil(t, c.Expires)
+}
+
+func TestDurationAliases(t *testing.T) {
+	aliases := map[string]time.Duration{"min": time.Minute, "hr": time.Hour, "d": 24 * time.Hour}
+	for raw, expected := range map[string]time.Duration{
+		"5min":       5 * time.Minute,
+		"2hr":        2 * time.Hour,
+		"1.5hr":      90 * time.Minute,
+		"2hr30m":     150 * time.Minute,
+		"1d12h":      36 * time.Hour,
+		"-10min30s":  -(10*time.Minute + 30*time.Second),
+		"1h30m":      90 * time.Minute,
+		"250ms":      250 * time.Millisecond,
+		" 3min ":     3 * time.Minute,
+		"1hr1min1ms": time.Hour + time.Minute + time.Millisecond,
+	} {
+		d, err := unmarshalDuration(t, enviper.New(viper.New()).WithDurationAliases(aliases), raw)
+		if assert.Nil(t, err, raw) {
+			assert.Equal(t, expected, d, raw)
+		}
+	}
+}
+
+func TestDurationAliasesInvalid(t *testing.T) {
+	aliases := map[string]time.Duration{"min": time.Minute}
+	for raw, message := range map[string]string{
+		"5mins":   `time: unknown unit`,
+		"5min2yr": `invalid duration "5min2yr"`,
+		"5MIN":    `time: unknown unit`,
+		"min":     `time: invalid duration`,
+	} {
+		_, err := unmarshalDuration(t, enviper.New(viper.New()).WithDurationAliases(aliases), raw)
+		if assert.NotNil(t, err, raw) {
+			assert.Contains(t, err.Error(), message, raw)
+		}
+	}
+}
+
+func TestDurationAliasesWithISO8601(t *testing.T) {
+	e := enviper.New(viper.New()).
+		WithISO8601Durations().
+		WithDurationAliases(map[string]time.Duration{"hr": time.Hour})
+	for raw, expected := range map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"3hr":     3 * time.Hour,
+	} {
+		d, err := unmarshalDuration(t, e, raw)
+		if assert.Nil(t, err, raw) {
+			assert.Equal(t, expected, d, raw)
+		}
+	}
+}
+
+func TestClockDurations(t *testing.T) {
+	for raw, expected := range map[string]time.Duration{
+		"01:30:00":    90 * time.Minute,
+		"1:02:03":     time.Hour + 2*time.Minute + 3*time.Second,
+		"100:00:00":   100 * time.Hour,
+		"05:30":       5*time.Minute + 30*time.Second,
+		"90:00":       90 * time.Minute,
+		"00:00:01.5":  1500 * time.Millisecond,
+		"-00:10:00":   -10 * time.Minute,
+		" 00:00:30 ":  30 * time.Second,
+		"1h30m":       90 * time.Minute,
+		"-1h2m3.5s":   -(time.Hour + 2*time.Minute + 3500*time.Millisecond),
+		"00:00:00.25": 250 * time.Millisecond,
+	} {
+		d, err := unmarshalDuration(t, enviper.New(viper.New()).WithClockDurations(), raw)
+		if assert.Nil(t, err, raw) {
+			assert.Equal(t, expected, d, raw)
+		}
+	}
+}
+
+func TestClockDurationsInvalid(t *testing.T) {
+	for raw, message := range map[string]string{
+		"01:60:00":             `clock duration "01:60:00" has 60 minutes, that must be less than 60`,
+		"01:00:75":             `clock duration "01:00:75" has 75 seconds, that must be less than 60`,
+		"05:60":                `clock duration "05:60" has 60 seconds, that must be less than 60`,
+		"1:2:3:4":              `invalid clock duration "1:2:3:4"`,
+		"01:xx":                `invalid clock duration "01:xx"`,
+		"01:":                  `invalid clock duration "01:"`,
+		"99999999999999:00:00": `clock duration "99999999999999:00:00" is out of range`,
+		"1 hour":               `time: unknown unit`,
+	} {
+		_, err := unmarshalDuration(t, enviper.New(viper.New()).WithClockDurations(), raw)
+		if assert.NotNil(t, err, raw) {
+			assert.Contains(t, err.Error(), message, raw)
+		}
+	}
+}
+
+func TestClockDurationsDisabled(t *testing.T) {
+	_, err := unmarshalDuration(t, enviper.New(viper.New()), "01:30:00")
+	assert.NotNil(t, err)
+}
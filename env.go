@@ -0,0 +1,76 @@
+package enviper
+
+import (
+	"os"
+	"strings"
+)
+
+// WithEnvSource sets the func listing env variables as `KEY=value` pairs, that is used instead of os.Environ,
+// e.g. to read env from a file or to feed it in tests.
+// Fields are set from the source by enviper itself, as viper reads only the process env.
+func (e *Enviper) WithEnvSource(environ func() []string) *Enviper {
+	e.envSource = environ
+	return e
+}
+
+// WithCaseInsensitiveEnvScan makes names of env variables match regardless of their case,
+// so `app_tags_0` or `App_Tags_0` set the same element as `APP_TAGS_0`.
+// When several variants are set, the one in the exact case wins.
+func (e *Enviper) WithCaseInsensitiveEnvScan() *Enviper {
+	e.caseInsensitiveEnv = true
+	return e
+}
+
+// customEnv reports whether env variables are looked up differently from viper,
+// so enviper has to set fields from them by itself
+func (e *Enviper) customEnv() bool {
+	return e.envSource != nil || e.caseInsensitiveEnv
+}
+
+// environ returns env variables as `KEY=value` pairs, names are uppercased when the scan is case insensitive.
+// Entries without "=", that a custom env source may return, are dropped.
+func (e *Enviper) environ() []string {
+	environ := os.Environ
+	if e.envSource != nil {
+		environ = e.envSource
+	}
+	env := environ()
+	pairs := make([]string, 0, len(env))
+	for _, kv := range env {
+		i := strings.Index(kv, "=")
+		if i == -1 {
+			continue
+		}
+		if e.caseInsensitiveEnv {
+			kv = strings.ToUpper(kv[:i]) + kv[i:]
+		}
+		pairs = append(pairs, kv)
+	}
+	return pairs
+}
+
+// lookupEnv does the same as os.LookupEnv, but with the env source and case sensitivity of Enviper
+func (e *Enviper) lookupEnv(name string) (string, bool) {
+	if !e.customEnv() {
+		return os.LookupEnv(name)
+	}
+	environ := os.Environ
+	if e.envSource != nil {
+		environ = e.envSource
+	}
+	var val string
+	var found bool
+	for _, kv := range environ() {
+		i := strings.Index(kv, "=")
+		if i == -1 {
+			continue
+		}
+		if kv[:i] == name {
+			return kv[i+1:], true
+		}
+		if !found && e.caseInsensitiveEnv && strings.EqualFold(kv[:i], name) {
+			val, found = kv[i+1:], true
+		}
+	}
+	return val, found
+}
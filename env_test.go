@@ -0,0 +1,121 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type EnvSourceConfig struct {
+	Host    string
+	Port    int
+	Tags    []string
+	Servers []struct {
+		Host string
+	}
+	Limits map[string]int
+}
+
+func envSource(env ...string) func() []string {
+	return func() []string {
+		return env
+	}
+}
+
+func TestEnvSource(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_HOST": "process"})()
+
+	e := enviper.New(viper.New()).WithEnvSource(envSource(
+		"APP_PORT=8080",
+		"APP_TAGS_1=b",
+		"APP_SERVERS_0_HOST=a",
+		"APP_LIMITS_READ=10",
+		"app_limits_write=5",
+	))
+	e.SetEnvPrefix("APP")
+
+	var c EnvSourceConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "", c.Host)
+		assert.Equal(t, 8080, c.Port)
+		assert.Equal(t, []string{"", "b"}, c.Tags)
+		if assert.Len(t, c.Servers, 1) {
+			assert.Equal(t, "a", c.Servers[0].Host)
+		}
+		assert.Equal(t, map[string]int{"read": 10}, c.Limits)
+	}
+}
+
+func TestEnvSourceMalformedEntries(t *testing.T) {
+	e := enviper.New(viper.New()).WithEnvSource(envSource(
+		"APP_TAGS_0",
+		"APP_PORT=8080",
+		"APP_LIMITS_READ",
+		"",
+		"APP_LIMITS_WRITE=5",
+	))
+	e.SetEnvPrefix("APP")
+
+	var c EnvSourceConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, 8080, c.Port)
+		assert.Nil(t, c.Tags)
+		assert.Equal(t, map[string]int{"write": 5}, c.Limits)
+	}
+	assert.Empty(t, e.LintEnv(&c))
+}
+
+func TestCaseInsensitiveEnvScan(t *testing.T) {
+	e := enviper.New(viper.New()).
+		WithEnvSource(envSource(
+			"app_host=lower",
+			"App_Port=8080",
+			"app_tags_0=a",
+			"APP_TAGS_1=b",
+			"App_Servers_0_Host=x",
+			"app_limits_read=10",
+		)).
+		WithCaseInsensitiveEnvScan()
+	e.SetEnvPrefix("APP")
+
+	var c EnvSourceConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "lower", c.Host)
+		assert.Equal(t, 8080, c.Port)
+		assert.Equal(t, []string{"a", "b"}, c.Tags)
+		if assert.Len(t, c.Servers, 1) {
+			assert.Equal(t, "x", c.Servers[0].Host)
+		}
+		assert.Equal(t, map[string]int{"read": 10}, c.Limits)
+	}
+}
+
+func TestCaseInsensitiveEnvScanExactCaseWins(t *testing.T) {
+	e := enviper.New(viper.New()).
+		WithEnvSource(envSource("app_host=lower", "APP_HOST=exact", "App_Host=mixed")).
+		WithCaseInsensitiveEnvScan()
+	e.SetEnvPrefix("APP")
+
+	var c EnvSourceConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "exact", c.Host)
+	}
+}
+
+func TestCaseInsensitiveEnvScanProcessEnv(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"app_port":   "8080",
+		"App_Tags_0": "a",
+	})()
+
+	e := enviper.New(viper.New()).WithCaseInsensitiveEnvScan()
+	e.SetEnvPrefix("APP")
+
+	var c EnvSourceConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, 8080, c.Port)
+		assert.Equal(t, []string{"a"}, c.Tags)
+	}
+}
@@ -1,8 +1,13 @@
 package enviper
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -14,14 +19,86 @@ import (
 // considering environment variables
 type Enviper struct {
 	*viper.Viper
-	tagName string
+	tagName               string
+	envKeyStyle           EnvKeyStyle
+	configKeyCase         func(string) string
+	keyPipeline           []func(string) string
+	strictTags            bool
+	strictSliceElements   bool
+	trimTrailingSeparator bool
+	csvSlices             bool
+	recordSeparators      bool
+	kvNameField           string
+	kvValueField          string
+	slicesFromJSONFile    bool
+	secretFiles           bool
+	numberedSlices        bool
+	indexedArrays         bool
+	globalSquash          bool
+	mapKeysEnv            bool
+	sliceIndexFormat      func(base string, i int) string
+	setterBinding         bool
+	iso8601Durations      bool
+	clockDurations        bool
+	envSource             func() []string
+	caseInsensitiveEnv    bool
+	durationAliases       map[string]time.Duration
+	unixTimestampUnit     time.Duration
+	defaultTimeLocation   *time.Location
+	decimalComma          bool
+	jsonNumberMode        JSONNumberMode
+	requireAllFields      bool
+	failFast              bool
+	isSetFunc             func(reflect.Value) bool
+	resolver              func(path string) (string, error)
+	valueTemplates        bool
+	skipStdlibInternals   bool
+	wholeConfigEnv        string
+	wholeConfigBelow      bool
+	bestEffortFileRead    bool
+	skipFileEnv           string
+	fileReadWarnings      []error
+	envWatchInterval      time.Duration
+	envPrefix             string
+	profileEnv            string
+	callEnvPrefix         bool
+	suffixPrefix          bool
+	userReplacer          *strings.Replacer
+	preserveReplacer      bool
+	// viperReplacer is the replacer read from the one set on viper directly, that is kept to compare with it
+	viperReplacer struct {
+		source   reflect.Value
+		replacer *strings.Replacer
+	}
+	overrides  map[string]interface{}
+	sliceTypes map[string]reflect.Type
+	sets       [][]string
+	mapEntries map[string]interface{}
+	// provided are the settings the config is decoded from, so validation knows which values are set explicitly
+	provided interface{}
+
+	stringDecoders map[reflect.Type]StringDecoder
+	wrappers       map[reflect.Type]wrapper
+	removed        map[string]string
+	fieldReaders   map[string]FieldReader
+	prefixCaptures map[string]prefixCapture
+	mapKeyParsers  map[reflect.Type]StringDecoder
+	boundEnvs      map[string]string
+	postDecoders   map[string]func(interface{}) (interface{}, error)
+	metadata       *Metadata
+
+	userDecodeHooks     []mapstructure.DecodeHookFunc
+	withoutDefaultHooks bool
 }
 
 // New returns an initialized Enviper instance
 func New(v *viper.Viper) *Enviper {
-	return &Enviper{
+	e := &Enviper{
 		Viper: v,
 	}
+	// the replacer is read before viper uses it, as strings.Replacer drops its pairs once it's built
+	e.readViperReplacer()
+	return e
 }
 
 const defaultTagName = "mapstructure"
@@ -40,89 +117,711 @@ func (e *Enviper) TagName() string {
 	return e.tagName
 }
 
+// SetEnvPrefix defines a prefix that env variables will use just like viper does.
+// Enviper keeps track of it to be able to look up indexed env variables (e.g. `PREFIX_SLICE_0`).
+// The prefix goes through the env key replacer too, so `my.app` becomes `MY_APP`.
+func (e *Enviper) SetEnvPrefix(in string) {
+	e.envPrefix = in
+	e.Viper.SetEnvPrefix(in)
+}
+
+// WithEnvPrefix does the same as SetEnvPrefix, but returns Enviper to chain options
+func (e *Enviper) WithEnvPrefix(in string) *Enviper {
+	e.SetEnvPrefix(in)
+	return e
+}
+
+// callOptions are the options of a single Unmarshal call that are passed along with decoder config options
+type callOptions struct {
+	envPrefix *string
+}
+
+// CallEnvPrefix returns the option of Unmarshal that sets env prefix for that call only,
+// so one Enviper could be used for configs of different components:
+//
+//	e.Unmarshal(&apiConfig, enviper.CallEnvPrefix("API"))
+//	e.Unmarshal(&workerConfig, enviper.CallEnvPrefix("WORKER"))
+//
+// Prefix set with SetEnvPrefix is used again by subsequent calls.
+func CallEnvPrefix(prefix string) viper.DecoderConfigOption {
+	return func(c *mapstructure.DecoderConfig) {
+		if o, ok := c.Result.(*callOptions); ok {
+			o.envPrefix = &prefix
+		}
+	}
+}
+
+// readCallOptions collects call options from decoder config options.
+// Options are applied to the probe config, so other options are applied there too, but it's thrown away.
+func readCallOptions(opts []viper.DecoderConfigOption) callOptions {
+	var o callOptions
+	probe := &mapstructure.DecoderConfig{Result: &o}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	return o
+}
+
+// WithSuffixPrefix puts env prefix at the end of env variable names instead of the beginning,
+// e.g. `PORT_MYAPP` and `SERVERS_0_HOST_MYAPP` instead of `MYAPP_PORT` and `MYAPP_SERVERS_0_HOST`,
+// for interop with legacy systems using such convention.
+func (e *Enviper) WithSuffixPrefix() *Enviper {
+	e.suffixPrefix = true
+	return e
+}
+
+// prefixSuffix returns the suffix of env variable names when env prefix is put at the end of them
+func (e *Enviper) prefixSuffix() string {
+	if !e.suffixPrefix || e.envPrefix == "" {
+		return ""
+	}
+	return "_" + e.envKey(e.envPrefix)
+}
+
 // Unmarshal unmarshals the config into a Struct just like viper does.
 // The difference between enviper and viper is in automatic overriding data from file by data from env variables
 func (e *Enviper) Unmarshal(rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	return e.unmarshal(nil, rawVal, opts...)
+}
+
+// UnmarshalKey unmarshals the section of the config by its key into a Struct just like viper does,
+// but with env variables of the section bound just like Unmarshal does,
+// so `e.UnmarshalKey("database", &db)` picks up `MYAPP_DATABASE_HOST`.
+// Metadata is filled by Unmarshal only.
+func (e *Enviper) UnmarshalKey(key string, rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	return e.unmarshal(strings.Split(key, "."), rawVal, opts...)
+}
+
+// unmarshal unmarshals the section of the config by its path, the whole config when the path is empty
+func (e *Enviper) unmarshal(path []string, rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	if call := readCallOptions(opts); call.envPrefix != nil {
+		defer func(prefix string) {
+			e.envPrefix = prefix
+			e.callEnvPrefix = false
+		}(e.envPrefix)
+		e.envPrefix = *call.envPrefix
+		e.callEnvPrefix = true
+	}
+	if e.strictTags {
+		if err := e.checkTags(reflect.TypeOf(rawVal), map[reflect.Type]bool{}); err != nil {
+			return err
+		}
+	}
+	if err := e.checkRemoved(); err != nil {
+		return err
+	}
+
 	if e.TagName() != defaultTagName {
 		opts = append(opts, func(c *mapstructure.DecoderConfig) {
 			c.TagName = e.TagName()
 		})
 	}
 
-	if err := e.Viper.ReadInConfig(); err != nil {
-		switch err.(type) {
-		case viper.ConfigFileNotFoundError:
-			// 	do nothing
-		default:
+	if err := e.readInConfig(); err != nil {
+		return err
+	}
+	if err := e.readWholeConfigEnv(); err != nil {
+		return err
+	}
+	// We need to unmarshal before the env binding to make sure that keys of maps are bound just like the struct fields
+	// We silence errors here because we'll unmarshal a second time.
+	// A fresh value is used, so the second unmarshal doesn't merge values into the ones from file (e.g. longer slices)
+	discovered := rawVal
+	if t := reflect.TypeOf(rawVal); t != nil && t.Kind() == reflect.Ptr {
+		discovered = reflect.New(t.Elem()).Interface()
+	}
+	discoverOpts := opts
+	if e.globalSquash {
+		discoverOpts = append(opts[:len(opts):len(opts)], e.squashOption())
+	}
+	if len(path) > 0 {
+		_ = e.Viper.UnmarshalKey(strings.Join(path, "."), discovered, discoverOpts...)
+	} else {
+		_ = e.Viper.Unmarshal(discovered, discoverOpts...)
+	}
+	if err := e.readEnvs(discovered, path...); err != nil {
+		return err
+	}
+	if e.metadata != nil && len(path) == 0 {
+		opts = append(opts, e.metadataOption())
+		e.metadata.EnvKeys = e.envKeys(discovered)
+		e.metadata.Shadowed = e.shadowedEnvs(discovered)
+	}
+	if err := e.decode(path, rawVal, opts...); err != nil {
+		return err
+	}
+	return e.finish(rawVal)
+}
+
+// finish evaluates templates, applies post decode funcs and validates the decoded config
+func (e *Enviper) finish(rawVal interface{}) error {
+	if e.valueTemplates {
+		if err := e.applyTemplates(rawVal); err != nil {
 			return err
 		}
 	}
-	// We need to unmarshal before the env binding to make sure that keys of maps are bound just like the struct fields
-	// We silence errors here because we'll unmarshal a second time
-	_ = e.Viper.Unmarshal(rawVal, opts...)
-	e.readEnvs(rawVal)
-	return e.Viper.Unmarshal(rawVal, opts...)
+	e.resolveRelPaths(rawVal)
+	if len(e.postDecoders) > 0 {
+		if err := e.applyPostDecoders(rawVal); err != nil {
+			return err
+		}
+	}
+	if e.resolver != nil {
+		if err := e.resolveMissing(rawVal); err != nil {
+			return err
+		}
+	}
+	return e.validate(rawVal)
+}
+
+var envKeyReplacer = strings.NewReplacer(".", "_")
+
+// SetEnvKeyReplacer sets the replacer of env variable names just like viper does.
+// Enviper keeps track of it, so Unmarshal uses it instead of the default one, that replaces `.` with `_`,
+// and derives names of env variables of slice elements and map keys with it too.
+// The replacer set on viper directly is used the same way, Unmarshal sets the default one only when there is none.
+func (e *Enviper) SetEnvKeyReplacer(r *strings.Replacer) {
+	e.userReplacer = r
+	e.Viper.SetEnvKeyReplacer(r)
+}
+
+// WithEnvKeyReplacer does the same as SetEnvKeyReplacer, but returns Enviper to chain options
+func (e *Enviper) WithEnvKeyReplacer(r *strings.Replacer) *Enviper {
+	e.SetEnvKeyReplacer(r)
+	return e
+}
+
+// WithPreserveExistingReplacer makes the replacer set on viper directly win over the one set with SetEnvKeyReplacer,
+// e.g. when code having only *viper.Viper changes it later. Unmarshal leaves it untouched
+// and derives names of all env variables with it, including slice elements and map keys.
+func (e *Enviper) WithPreserveExistingReplacer() *Enviper {
+	e.preserveReplacer = true
+	return e
+}
+
+// separator returns the separator of segments of env variable names, `_` by default
+func (e *Enviper) separator() string {
+	return e.envKey(".")
+}
+
+// replacer returns the replacer of env variable names used by Unmarshal
+func (e *Enviper) replacer() *strings.Replacer {
+	if e.userReplacer != nil && !e.preserveReplacer {
+		return e.userReplacer
+	}
+	if r, _ := e.readViperReplacer(); r != nil {
+		return r
+	}
+	if e.userReplacer != nil {
+		return e.userReplacer
+	}
+	return envKeyReplacer
+}
+
+func (e *Enviper) readEnvs(rawVal interface{}, prev ...string) error {
+	switch _, set := e.readViperReplacer(); {
+	case e.preserveReplacer && set:
+	case e.userReplacer != nil:
+		e.Viper.SetEnvKeyReplacer(e.userReplacer)
+	case !set:
+		e.Viper.SetEnvKeyReplacer(envKeyReplacer)
+	}
+	e.overrides = map[string]interface{}{}
+	e.sliceTypes = map[string]reflect.Type{}
+	e.sets = nil
+	e.mapEntries = map[string]interface{}{}
+	if err := e.bindEnvs(rawVal, prev...); err != nil {
+		return err
+	}
+	if err := e.readJSONFiles(rawVal, prev...); err != nil {
+		return err
+	}
+	if err := e.readSecretFiles(rawVal, prev...); err != nil {
+		return err
+	}
+	e.readPrefixCaptures()
+	return e.readFields()
+}
+
+// decode does the same as viper.Unmarshal does for the section of the config by its path,
+// but applies values of indexed env variables on top of viper settings
+func (e *Enviper) decode(section []string, rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	settings := e.Viper.AllSettings()
+	for _, path := range e.sets {
+		if set := e.setFromList(settings, path); set != nil {
+			settings = setPath(settings, path, set).(map[string]interface{})
+		}
+	}
+	entries := make([]string, 0, len(e.mapEntries))
+	for key := range e.mapEntries {
+		entries = append(entries, key)
+	}
+	sort.Strings(entries)
+	for _, key := range entries {
+		if path := strings.Split(key, "."); getPath(settings, path) == nil {
+			settings = setPath(settings, path, e.mapEntries[key]).(map[string]interface{})
+		}
+	}
+	keys := make([]string, 0, len(e.overrides))
+	for key := range e.overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := strings.Split(key, ".")
+		var err error
+		if settings, err = e.expandSlices(settings, path, opts...); err != nil {
+			return err
+		}
+		settings = setPath(settings, path, e.overrides[key]).(map[string]interface{})
+	}
+
+	var input interface{} = settings
+	if len(section) > 0 {
+		input = getPath(settings, section)
+	}
+	if err := e.checkHex(settings, rawVal, section); err != nil {
+		return err
+	}
+	e.provided = input
+	if err := decode(input, e.decoderConfig(rawVal, opts...)); err != nil {
+		return e.firstDecodeError(err)
+	}
+	if e.setterBinding {
+		return e.applySetters(reflect.ValueOf(rawVal), input, opts...)
+	}
+	return nil
+}
+
+// decoderConfig returns the same config viper uses by default, but with enviper's decode hooks.
+// GlobalDecodeHooks, hooks registered with WithDecodeHook and the one passed with viper.DecodeHook option
+// are composed with enviper's ones in that order, instead of replacing them.
+func (e *Enviper) decoderConfig(rawVal interface{}, opts ...viper.DecoderConfigOption) *mapstructure.DecoderConfig {
+	c := &mapstructure.DecoderConfig{
+		Result:           rawVal,
+		WeaklyTypedInput: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	hooks := append(append([]mapstructure.DecodeHookFunc{}, GlobalDecodeHooks...), e.userDecodeHooks...)
+	if c.DecodeHook != nil {
+		hooks = append(hooks, c.DecodeHook)
+	}
+	c.DecodeHook = mapstructure.ComposeDecodeHookFunc(append(hooks, e.decodeHooks()...)...)
+	return c
+}
+
+func decode(input interface{}, c *mapstructure.DecoderConfig) error {
+	decoder, err := mapstructure.NewDecoder(c)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(input)
 }
 
-func (e *Enviper) readEnvs(rawVal interface{}) {
-	e.Viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	e.bindEnvs(rawVal)
+func (e *Enviper) bindEnvs(in interface{}, prev ...string) error {
+	var errs []string
+	profile := e.profile()
+	e.walk(field{path: prev, env: prev, value: reflect.ValueOf(in)}, func(f field) {
+		if e.failed(errs) {
+			return
+		}
+		switch {
+		case e.isSet(f):
+			e.sets = append(e.sets, f.path)
+			if err := e.overrideSet(f); err != nil {
+				errs = append(errs, err.Error())
+			}
+		case f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()):
+			// maps are bound key by key
+			e.createMapEntries(f)
+		case f.indexed || e.customEnv():
+			e.overrideFromEnv(f)
+		case e.viperDerivesEnvName(f):
+			// viper derives the env name from the key with its own prefix and replacer
+			// Viper.BindEnv will never return error because env is always non empty string
+			_ = e.Viper.BindEnv(strings.Join(f.path, "."))
+		default:
+			// env name differs from the one viper derives from the key, so it's bound explicitly
+			_ = e.Viper.BindEnv(e.configKey(f.path), e.fieldEnvName(f))
+		}
+		if f.defaultValue != "" {
+			e.Viper.SetDefault(e.configKey(f.path), f.defaultValue)
+		}
+		if profile != "" && e.profileScoped(f) {
+			e.overrideFromProfile(f, profile)
+		}
+		if f.value.IsValid() && e.isNumbered(f.value.Type()) {
+			e.overrideNumbered(f)
+		} else if kind := f.value.Kind(); (kind == reflect.Slice || kind == reflect.Array) && !e.isLeaf(f.value.Type()) {
+			e.sliceTypes[strings.Join(f.path, ".")] = f.value.Type()
+		}
+	})
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// viperDerivesEnvName reports whether the env name of the field is the one viper derives from its key
+func (e *Enviper) viperDerivesEnvName(f field) bool {
+	return e.envKeyStyle == EnvKeyGoName && e.configKeyCase == nil && e.keyPipeline == nil && len(f.envNames) == 0 &&
+		!e.callEnvPrefix && !e.suffixPrefix && e.boundEnvs[strings.ToLower(strings.Join(f.path, "."))] == ""
 }
 
-func (e *Enviper) bindEnvs(in interface{}, prev ...string) {
-	ifv := reflect.ValueOf(in)
-	if ifv.Kind() == reflect.Ptr {
+// field is a value found while walking the config
+type field struct {
+	path []string
+	// env is the path the env variable name is derived from, it differs from path only by names of struct fields
+	env   []string
+	value reflect.Value
+	// opts are the options of the tag of struct field
+	opts tagOptions
+	// indexed is true for values inside of slice elements, viper can't bind them
+	indexed bool
+	// envNames are the names of env variables from `env` tag of struct field in order of priority
+	envNames []string
+	// defaultValue is the value of `default` tag of struct field
+	defaultValue string
+}
+
+func (f field) child(key string, value reflect.Value) field {
+	return field{path: appendPath(f.path, key), env: appendPath(f.env, key), value: value, indexed: f.indexed}
+}
+
+// walk traverses the value and calls leaf for every field that could be set by env variable and for every map.
+// Elements of slices are walked only when there are indexed env variables for them (e.g. `PREFIX_SLICE_0_FIELD`).
+// Keys of maps missing in the value are walked when there are env variables for them (e.g. `PREFIX_MAP_KEY_FIELD`).
+func (e *Enviper) walk(f field, leaf func(f field)) {
+	elements := func(f field) []int {
+		if e.isNumbered(f.value.Type()) {
+			return nil
+		}
+		return e.envIndexes(f.env)
+	}
+	var visit func(f field)
+	visit = func(f field) {
+		leaf(f)
+		if f.value.Kind() != reflect.Map || e.isLeaf(f.value.Type()) {
+			return
+		}
+		for _, key := range e.envMapKeys(f) {
+			e.walkElements(f.child(key, reflect.New(f.value.Type().Elem()).Elem()), visit, elements)
+		}
+	}
+	e.walkElements(f, visit, elements)
+}
+
+// walkElements does the same as walk, but elements of slices to walk are chosen by elements func
+func (e *Enviper) walkElements(f field, leaf func(f field), elements func(f field) []int) {
+	ifv := f.value
+	if ifv.Kind() == reflect.Interface && !ifv.IsNil() {
+		ifv = ifv.Elem()
+	}
+	// unwrap all levels of pointers, e.g. when **Config is passed
+	for ifv.Kind() == reflect.Ptr {
+		if ifv.IsNil() {
+			ifv = reflect.New(ifv.Type().Elem())
+		}
 		ifv = ifv.Elem()
 	}
+	f.value = ifv
+
+	if ifv.IsValid() && e.isLeaf(ifv.Type()) {
+		leaf(f)
+		return
+	}
 
 	switch ifv.Kind() {
 	case reflect.Struct:
 		for i := 0; i < ifv.NumField(); i++ {
 			fv := ifv.Field(i)
-			if fv.Kind() == reflect.Ptr {
-				if fv.IsZero() {
-					fv = reflect.New(fv.Type().Elem()).Elem()
-				} else {
-					fv = fv.Elem()
-				}
-			}
 			t := ifv.Type().Field(i)
-			tv, ok := t.Tag.Lookup(e.TagName())
-			if ok {
-				if index := strings.Index(tv, ","); index != -1 {
-					if tv[:index] == "-" {
-						continue
-					}
-
-					// If "squash" is specified in the tag, we squash the field down.
-					if strings.Contains(tv[index+1:], "squash") {
-						e.bindEnvs(fv.Interface(), prev...)
-						continue
-					}
-
-					tv = tv[:index]
-				}
-
-				if tv == "" {
-					tv = t.Name
-				}
-			} else {
-				tv = t.Name
+			name, opts := parseTag(t.Tag.Get(e.TagName()))
+			if name == "-" || e.isSkipped(t.Type) {
+				continue
+			}
+
+			// If "squash" is specified in the tag, we squash the field down ignoring the name.
+			if e.isSquashed(t, name, opts) {
+				squashed := f
+				squashed.value = fv
+				e.walkElements(squashed, leaf, elements)
+				continue
+			}
+
+			segment := name
+			if name == "" {
+				name = t.Name
+				segment = e.envSegment(t)
 			}
 
-			e.bindEnvs(fv.Interface(), append(prev, tv)...)
+			child := f.child(name, fv)
+			child.env = appendPath(f.env, segment)
+			child.opts = opts
+			if !child.indexed {
+				child.envNames = e.parseEnvTag(t.Tag.Get(envTagName))
+				child.defaultValue = t.Tag.Get(defaultValueTagName)
+			}
+			e.walkElements(child, leaf, elements)
 		}
 	case reflect.Map:
+		leaf(f)
 		iter := ifv.MapRange()
 		for iter.Next() {
-			if key, ok := iter.Key().Interface().(string); ok {
-				e.bindEnvs(iter.Value().Interface(), append(prev, key)...)
+			// keys could be of named string types, e.g. `type Environment string`
+			if key := iter.Key(); key.Kind() == reflect.String {
+				e.walkElements(f.child(key.String(), iter.Value()), leaf, elements)
+			}
+		}
+	case reflect.Slice:
+		leaf(f)
+		for _, i := range elements(f) {
+			var elem reflect.Value
+			if i < ifv.Len() {
+				elem = ifv.Index(i)
+			} else {
+				elem = reflect.New(ifv.Type().Elem()).Elem()
+			}
+			child := f.child(strconv.Itoa(i), elem)
+			child.indexed = true
+			e.walkElements(child, leaf, elements)
+		}
+	case reflect.Array:
+		leaf(f)
+		if !e.indexedArrays {
+			return
+		}
+		for _, i := range elements(f) {
+			if i < ifv.Len() {
+				child := f.child(strconv.Itoa(i), ifv.Index(i))
+				child.indexed = true
+				e.walkElements(child, leaf, elements)
 			}
 		}
 	default:
-		env := strings.Join(prev, ".")
-		// Viper.BindEnv will never return error
-		// because env is always non empty string
-		_ = e.Viper.BindEnv(env)
+		leaf(f)
+	}
+}
+
+// overrideFromEnv collects the value of env variable bound to the field
+func (e *Enviper) overrideFromEnv(f field) {
+	if val, ok := e.lookupEnv(e.fieldEnvName(f)); ok && val != "" {
+		e.overrides[strings.Join(f.path, ".")] = val
+	}
+}
+
+// envName returns the name of env variable bound to the env path just like viper does
+func (e *Enviper) envName(path []string) string {
+	if suffix := e.prefixSuffix(); suffix != "" {
+		return e.prefixedEnvName("", path) + suffix
+	}
+	return e.prefixedEnvName(e.envPrefix, path)
+}
+
+// prefixedEnvName returns the name of env variable bound to the env path with the prefix
+func (e *Enviper) prefixedEnvName(prefix string, path []string) string {
+	if e.sliceIndexFormat != nil {
+		return e.formattedEnvName(prefix, path)
+	}
+	key := strings.Join(path, ".")
+	if e.keyPipeline != nil {
+		// the prefix is transformed on its own, so the pipeline sees only keys joined by `.`
+		switch {
+		case prefix != "" && key != "":
+			return e.envKey(prefix) + "_" + e.envKey(key)
+		case prefix != "":
+			return e.envKey(prefix)
+		}
+		return e.envKey(key)
+	}
+	if prefix != "" && key != "" {
+		key = prefix + "_" + key
+	} else if prefix != "" {
+		key = prefix
+	}
+	return e.envKey(key)
+}
+
+// envRest returns the rest of env variable name after the env path, e.g. `0_HOST` of `PREFIX_SERVERS_0_HOST`,
+// and whether the env variable is nested in the path at all
+func (e *Enviper) envRest(env string, path []string) (string, bool) {
+	name := e.envName(path)
+	if suffix := e.prefixSuffix(); suffix != "" {
+		if !strings.HasSuffix(env, suffix) {
+			return "", false
+		}
+		env, name = env[:len(env)-len(suffix)], name[:len(name)-len(suffix)]
+	}
+	if name == "" {
+		return env, true
+	}
+	if !strings.HasPrefix(env, name+e.separator()) {
+		return "", false
+	}
+	return env[len(name)+len(e.separator()):], true
+}
+
+// envIndexes returns sorted indexes of slice elements that are set by env variables
+func (e *Enviper) envIndexes(path []string) []int {
+	if e.sliceIndexFormat != nil {
+		return e.formattedEnvIndexes(path)
+	}
+	sep := e.separator()
+	seen := map[int]bool{}
+	var indexes []int
+	for _, kv := range e.environ() {
+		segment, ok := e.envRest(kv[:strings.Index(kv, "=")], path)
+		if !ok {
+			continue
+		}
+		if end := strings.Index(segment, sep); end != -1 {
+			segment = segment[:end]
+		}
+		i, ok := sliceIndex(segment)
+		if !ok || seen[i] {
+			continue
+		}
+		seen[i] = true
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// envMapKeys returns sorted keys of the map that are set only by env variables.
+// The key is what is left of the env variable name after the map prefix and the suffix of an element field,
+// the longest suffix wins when several fields match.
+func (e *Enviper) envMapKeys(f field) []string {
+	t := f.value.Type()
+	if _, ok := e.mapKeyParsers[t.Key()]; !ok && t.Key().Kind() != reflect.String {
+		return nil
+	}
+	existing := map[string]bool{}
+	iter := f.value.MapRange()
+	for iter.Next() {
+		// other keys are not walked, so they are found by env variables
+		if key := iter.Key(); key.Kind() == reflect.String {
+			existing[e.envName(appendPath(f.env, key.String()))] = true
+		}
+	}
+	if listed, ok := e.listedMapKeys(f); ok {
+		var keys []string
+		for _, key := range listed {
+			if !existing[e.envName(appendPath(f.env, key))] {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+
+	// env suffixes of fields of the element, the empty one means the element is set as a whole,
+	// nested maps of the element are matched by their prefixes, as their keys are dynamic too,
+	// the empty one means the element is a map itself
+	var suffixes, maps []string
+	e.walkElements(field{value: reflect.New(t.Elem()).Elem()}, func(elem field) {
+		name := e.envKey(strings.Join(elem.env, "."))
+		if elem.value.Kind() != reflect.Map || e.isLeaf(elem.value.Type()) {
+			suffixes = append(suffixes, name)
+		} else if name != "" || len(elem.path) == 0 {
+			maps = append(maps, name)
+		}
+	}, func(field) []int { return nil })
+	sort.Slice(suffixes, func(i, j int) bool {
+		return len(suffixes[i]) > len(suffixes[j])
+	})
+
+	sep := e.separator()
+	seen := map[string]bool{}
+	var keys []string
+	for _, kv := range e.environ() {
+		i := strings.Index(kv, "=")
+		rest, ok := e.envRest(kv[:i], f.env)
+		if !ok || kv[i+1:] == "" {
+			continue
+		}
+		key := strings.ToLower(mapKey(rest, sep, suffixes, maps))
+		if key != "" && !existing[e.envName(appendPath(f.env, key))] && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mapKey returns the key of map element that is set by the rest of env variable name after the map prefix.
+// The longest matching suffix wins, then the first occurrence of nested map prefix.
+// When the element is a map itself, its key is the first segment, e.g. `API` of `API_PROD`.
+func mapKey(rest, sep string, suffixes, maps []string) string {
+	for _, suffix := range suffixes {
+		if suffix == "" {
+			return rest
+		}
+		if strings.HasSuffix(rest, sep+suffix) {
+			return rest[:len(rest)-len(suffix)-len(sep)]
+		}
+	}
+	for _, m := range maps {
+		if m == "" {
+			if i := strings.Index(rest, sep); i > 0 {
+				return rest[:i]
+			}
+			continue
+		}
+		if i := strings.Index(rest, sep+m+sep); i != -1 {
+			return rest[:i]
+		}
+	}
+	return ""
+}
+
+// appendPath returns a new path, so paths of siblings never share the underlying array
+func appendPath(path []string, key string) []string {
+	return append(path[:len(path):len(path)], key)
+}
+
+// setPath returns a copy of node with the value set by the path.
+// Missing maps and slices are created, slices are grown when needed.
+// Containers on the path are copied, because they could be owned by viper.
+func setPath(node interface{}, path []string, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	key := path[0]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(n)+1)
+		k := key
+		for nk, nv := range n {
+			m[nk] = nv
+			if strings.EqualFold(nk, key) {
+				k = nk
+			}
+		}
+		m[k] = setPath(m[k], path[1:], value)
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(n)+1)
+		for nk, nv := range n {
+			m[fmt.Sprint(nk)] = nv
+		}
+		return setPath(m, path, value)
+	case []interface{}:
+		if i, ok := sliceIndex(key); ok {
+			l := make([]interface{}, len(n), len(n)+i+1)
+			copy(l, n)
+			for len(l) <= i {
+				l = append(l, nil)
+			}
+			l[i] = setPath(l[i], path[1:], value)
+			return l
+		}
+	}
+	// there is no container at the moment, so create it
+	if _, ok := sliceIndex(key); ok {
+		return setPath([]interface{}{}, path, value)
 	}
+	return setPath(map[string]interface{}{}, path, value)
 }
@@ -3,11 +3,15 @@ package enviper_test
 import (
 	"errors"
 	"fmt"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/iamolegga/enviper"
 	"github.com/spf13/viper"
@@ -21,8 +25,10 @@ func TestUnmarshal(t *testing.T) {
 
 type UnmarshalSuite struct {
 	suite.Suite
-	v   *viper.Viper
-	env map[string]string
+	v       *viper.Viper
+	env     map[string]string
+	tmpEnv  []string
+	tmpDirs []string
 }
 
 func (s *UnmarshalSuite) SetupSuite() {
@@ -31,7 +37,16 @@ func (s *UnmarshalSuite) SetupSuite() {
 func (s *UnmarshalSuite) SetupTest() {
 	s.v = viper.New()
 }
-func (s *UnmarshalSuite) TearDownTest()  {}
+func (s *UnmarshalSuite) TearDownTest() {
+	for _, k := range s.tmpEnv {
+		os.Unsetenv(k)
+	}
+	s.tmpEnv = nil
+	for _, dir := range s.tmpDirs {
+		os.RemoveAll(dir)
+	}
+	s.tmpDirs = nil
+}
 func (s *UnmarshalSuite) TearDownSuite() {}
 
 func (s *UnmarshalSuite) TestThrowsErrorWhenBrokenConfig() {
@@ -87,8 +102,38 @@ func (s *UnmarshalSuite) TestOnlyEnvs() {
 	s.Equal(false, c.QUX.Quuux)
 	s.Equal("testptr3", c.QUX.QuuuxPtrUnset.Value)
 
-	// TODO: known bug, that maps can not be set when there is no config file. Uncomment and fix
-	//s.Equal(true, c.QuxMap["key1"].Quuux)
+	s.Equal(true, c.QuxMap["key1"].Quuux)
+}
+
+func (s *UnmarshalSuite) TestPointerToPointer() {
+	s.setupFileConfig()
+	s.setupEnvConfig()
+	defer s.tearDownEnvConfig()
+
+	var c *Config
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Require().NotNil(c)
+	s.Equal("fooooo", c.Foo)
+	s.Equal(2, c.Bar.BAZ)
+	s.Equal(true, c.QuxMap["key1"].Quuux)
+	s.Equal("testptr3", c.QuuuxPtrUnset.Value)
+}
+
+func (s *UnmarshalSuite) TestPointerToPointerWithoutConfig() {
+	s.setupEnvConfig()
+	defer s.tearDownEnvConfig()
+
+	c := new(*Config)
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(c))
+
+	s.Require().NotNil(*c)
+	s.Equal("fooooo", (*c).Foo)
+	s.Equal(2, (*c).Bar.BAZ)
 }
 
 func (s *UnmarshalSuite) TestPrimitiveMap() {
@@ -137,6 +182,243 @@ func (s *UnmarshalSuite) TestConfigWithEnvs() {
 	s.Equal("testptr3", c.QuuuxPtrUnset.Value)
 }
 
+func (s *UnmarshalSuite) TestSliceOfStructsByIndex() {
+	s.setupTmpConfig(`
+servers:
+  - host: first
+    tls:
+      cert: first.crt
+      key: first.key
+  - host: second
+`)
+	s.setupTmpEnv(map[string]string{
+		"PREF_SERVERS_0_TLS_CERT":         "env.crt",
+		"PREF_SERVERS_1_TLS_KEY":          "second.key",
+		"PREF_SERVERS_2_HOST":             "third",
+		"PREF_SERVERS_2_TLS_CA_0":         "ca0.crt",
+		"PREF_SERVERS_2_TLS_OPTIONS_MODE": "strict",
+	})
+
+	var c SlicesConfig
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Len(c.Servers, 3)
+	s.Equal("first", c.Servers[0].Host)
+	s.Equal("env.crt", c.Servers[0].TLS.Cert)
+	s.Equal("first.key", c.Servers[0].TLS.Key)
+	s.Equal("second", c.Servers[1].Host)
+	s.Equal("second.key", c.Servers[1].TLS.Key)
+	s.Equal("third", c.Servers[2].Host)
+	s.Equal([]string{"ca0.crt"}, c.Servers[2].TLS.CA)
+	s.Equal("strict", c.Servers[2].TLS.Options.Mode)
+}
+
+func (s *UnmarshalSuite) TestSliceOfPointersByIndexWithoutConfig() {
+	s.setupTmpEnv(map[string]string{
+		"SERVERPTRS_1_TLS_CERT": "second.crt",
+		"TAGS_0":                "a",
+		"TAGS_1":                "b",
+	})
+
+	var c SlicesConfig
+	e := enviper.New(s.v)
+	s.Nil(e.Unmarshal(&c))
+
+	s.Len(c.ServerPtrs, 2)
+	s.Nil(c.ServerPtrs[0])
+	s.Equal("second.crt", c.ServerPtrs[1].TLS.Cert)
+	s.Equal([]string{"a", "b"}, c.Tags)
+}
+
+func (s *UnmarshalSuite) TestMapWithNamedStringKeys() {
+	s.setupTmpConfig(`
+environments:
+  prod:
+    host: prod.host
+    replicas: 3
+  dev:
+    host: dev.host
+`)
+	s.setupTmpEnv(map[string]string{
+		"PREF_ENVIRONMENTS_PROD_REPLICAS": "5",
+		"PREF_ENVIRONMENTS_DEV_HOST":      "localhost",
+	})
+
+	var c struct {
+		Environments map[Environment]struct {
+			Host     string
+			Replicas int
+		}
+	}
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Equal("prod.host", c.Environments["prod"].Host)
+	s.Equal(5, c.Environments["prod"].Replicas)
+	s.Equal("localhost", c.Environments["dev"].Host)
+}
+
+func (s *UnmarshalSuite) TestMapOfPointersCreatedFromEnv() {
+	s.setupTmpEnv(map[string]string{
+		"PREF_SERVERS_WEB_HOST":             "web.host",
+		"PREF_SERVERS_WEB_TLS_CERT":         "web.crt",
+		"PREF_SERVERS_API_TLS_CA":           "ca0.crt,ca1.crt",
+		"PREF_SERVERS_API_TLS_OPTIONS_MODE": "strict",
+	})
+
+	var c struct {
+		Servers map[string]*Server
+	}
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Len(c.Servers, 2)
+	if s.NotNil(c.Servers["web"]) {
+		s.Equal("web.host", c.Servers["web"].Host)
+		s.Equal("web.crt", c.Servers["web"].TLS.Cert)
+	}
+	if s.NotNil(c.Servers["api"]) {
+		s.Equal([]string{"ca0.crt", "ca1.crt"}, c.Servers["api"].TLS.CA)
+		s.Equal("strict", c.Servers["api"].TLS.Options.Mode)
+	}
+}
+
+func (s *UnmarshalSuite) TestMapOfPointersMergedWithEnvOnlyKeys() {
+	s.setupTmpConfig(`
+servers:
+  web:
+    host: web.host
+`)
+	s.setupTmpEnv(map[string]string{
+		"PREF_SERVERS_WEB_TLS_KEY":      "web.key",
+		"PREF_SERVERS_BACK_OFFICE_HOST": "office.host",
+	})
+
+	var c struct {
+		Servers map[string]*Server
+	}
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Len(c.Servers, 2)
+	s.Equal("web.host", c.Servers["web"].Host)
+	s.Equal("web.key", c.Servers["web"].TLS.Key)
+	// keys could contain the separator, as the rest of env variable name matches the field
+	if s.NotNil(c.Servers["back_office"]) {
+		s.Equal("office.host", c.Servers["back_office"].Host)
+	}
+}
+
+func (s *UnmarshalSuite) TestMapKeysOnlyInEnv() {
+	s.setupTmpConfig(`
+limits:
+  read: 1
+`)
+	s.setupTmpEnv(map[string]string{
+		"PREF_SERVICES_AUTH_URL":     "http://auth",
+		"PREF_SERVICES_BILLING_URL":  "http://billing",
+		"PREF_SERVICES_BILLING_PORT": "8080",
+		"PREF_LIMITS_READ":           "10",
+		"PREF_LIMITS_WRITE":          "5",
+	})
+
+	var c struct {
+		Services map[string]struct {
+			URL  string
+			Port int
+		}
+		Limits map[string]int
+	}
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Len(c.Services, 2)
+	s.Equal("http://auth", c.Services["auth"].URL)
+	s.Equal("http://billing", c.Services["billing"].URL)
+	s.Equal(8080, c.Services["billing"].Port)
+	// keys found in both file and env are not duplicated
+	s.Equal(map[string]int{"read": 10, "write": 5}, c.Limits)
+}
+
+func (s *UnmarshalSuite) TestMapOfStructsWithoutConfigFile() {
+	s.setupTmpEnv(map[string]string{
+		"MYAPP_SERVICES_PAYMENTS_URL":          "http://payments",
+		"MYAPP_SERVICES_PAYMENTS_TIMEOUT":      "5s",
+		"MYAPP_SERVICES_PAYMENTS_RETRY_MAX":    "3",
+		"MYAPP_SERVICES_USER_PROFILES_URL":     "http://profiles",
+		"MYAPP_SERVICES_USER_PROFILES_ENABLED": "true",
+	})
+
+	type ServiceConfig struct {
+		URL     string
+		Timeout time.Duration
+		Enabled bool
+		Retry   struct{ Max int }
+	}
+	var c struct {
+		Services map[string]ServiceConfig
+	}
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("MYAPP")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Len(c.Services, 2)
+	s.Equal("http://payments", c.Services["payments"].URL)
+	s.Equal(5*time.Second, c.Services["payments"].Timeout)
+	s.Equal(3, c.Services["payments"].Retry.Max)
+	s.Equal("http://profiles", c.Services["user_profiles"].URL)
+	s.True(c.Services["user_profiles"].Enabled)
+}
+
+func (s *UnmarshalSuite) TestNamedMapType() {
+	s.setupTmpConfig(`
+headers:
+  accept: text/plain
+`)
+	s.setupTmpEnv(map[string]string{
+		"PREF_HEADERS_ACCEPT":          "application/json",
+		"PREF_HEADERS_X":               "y",
+		"PREF_UPSTREAMS_API_HEADERS_X": "z",
+	})
+
+	var c struct {
+		Headers   Headers
+		Upstreams map[string]struct {
+			Headers *Headers
+		}
+	}
+	e := enviper.New(s.v)
+	e.SetEnvPrefix("PREF")
+	s.Nil(e.Unmarshal(&c))
+
+	s.Equal(Headers{"accept": "application/json", "x": "y"}, c.Headers)
+	if s.NotNil(c.Upstreams["api"].Headers) {
+		s.Equal(Headers{"x": "z"}, *c.Upstreams["api"].Headers)
+	}
+}
+
+func (s *UnmarshalSuite) setupTmpConfig(content string) {
+	dir, err := ioutil.TempDir("", "enviper")
+	s.Require().Nil(err)
+	s.tmpDirs = append(s.tmpDirs, dir)
+	s.Require().Nil(ioutil.WriteFile(path.Join(dir, "config.yaml"), []byte(content), 0600))
+	s.v.AddConfigPath(dir)
+	s.v.SetConfigName("config")
+}
+
+func (s *UnmarshalSuite) setupTmpEnv(env map[string]string) {
+	for k, v := range env {
+		s.Require().Nil(os.Setenv(k, v))
+		s.tmpEnv = append(s.tmpEnv, k)
+	}
+}
+
 func (s *UnmarshalSuite) setupFileConfig() {
 	cwd, _ := os.Getwd()
 	s.v.AddConfigPath(cwd)
@@ -211,6 +493,28 @@ type PtrTest struct {
 	Value string
 }
 
+type Environment string
+
+type Headers map[string]string
+
+type SlicesConfig struct {
+	Servers    []Server
+	ServerPtrs []*Server
+	Tags       []string
+}
+
+type Server struct {
+	Host string
+	TLS  struct {
+		Cert    string
+		Key     string
+		CA      []string
+		Options struct {
+			Mode string
+		}
+	}
+}
+
 func TestNew(t *testing.T) {
 	v := viper.New()
 	assert.Exactly(t, &enviper.Enviper{Viper: v}, enviper.New(v))
@@ -268,3 +572,449 @@ Barry:
 	// 2
 	// false
 }
+
+// setenv sets env variables and returns the function that unsets them
+func setenv(t *testing.T, env map[string]string) func() {
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+// writeConfig writes config.yaml to a temp dir and returns the dir and the function that removes it
+func writeConfig(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "enviper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "config.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCallEnvPrefix(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"API_FOO":       "api",
+		"API_BAR_BAZ":   "1",
+		"WORKER_FOO":    "worker",
+		"WORKER_TAGS_1": "b",
+		"SHARED_FOO":    "shared",
+	})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("SHARED")
+
+	var api Config
+	assert.Nil(t, e.Unmarshal(&api, enviper.CallEnvPrefix("API")))
+	assert.Equal(t, "api", api.Foo)
+	assert.Equal(t, 1, api.Bar.BAZ)
+
+	var worker struct {
+		Foo  string
+		Tags []string
+	}
+	assert.Nil(t, e.Unmarshal(&worker, enviper.CallEnvPrefix("WORKER")))
+	assert.Equal(t, "worker", worker.Foo)
+	assert.Equal(t, []string{"", "b"}, worker.Tags)
+
+	var shared Config
+	assert.Nil(t, e.Unmarshal(&shared))
+	assert.Equal(t, "shared", shared.Foo)
+	assert.Equal(t, 0, shared.Bar.BAZ)
+}
+
+func TestPreserveExistingReplacer(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_DB__HOST":   "nested",
+		"APP_DB_HOST":    "flat",
+		"APP_TAGS__1":    "b",
+		"APP_TAGS_0":     "tracked",
+		"APP_LABELS__OK": "yes",
+	})()
+
+	type config struct {
+		DB struct {
+			Host string
+		}
+		DBHost string `mapstructure:"db_host"`
+		Tags   []string
+		Labels map[string]string
+	}
+	v := viper.New()
+	e := enviper.New(v).WithEnvKeyReplacer(strings.NewReplacer(".", "_")).WithPreserveExistingReplacer()
+	e.SetEnvPrefix("APP")
+	// the replacer is changed by code that has only viper
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "__"))
+
+	var c config
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "nested", c.DB.Host)
+	assert.Equal(t, "flat", c.DBHost)
+	assert.Equal(t, []string{"", "b"}, c.Tags)
+	assert.Equal(t, map[string]string{"ok": "yes"}, c.Labels)
+
+	// without the option the replacer set with SetEnvKeyReplacer wins
+	v = viper.New()
+	e = enviper.New(v).WithEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	e.SetEnvPrefix("APP")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "__"))
+
+	c = config{}
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "flat", c.DB.Host)
+	assert.Equal(t, []string{"tracked"}, c.Tags)
+}
+
+func TestExistingReplacerIsKeptByDefault(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP__BAR__BAZ":   "1",
+		"APP_BAR_BAZ":     "2",
+		"APP__TAGS__0":    "a",
+		"APP__TAGS__1":    "b",
+		"APP_TAGS_0":      "ignored",
+		"APP__LABELS__OK": "yes",
+	})()
+
+	var c struct {
+		Bar struct {
+			Baz int
+		}
+		Tags   []string
+		Labels map[string]string
+	}
+	e := enviper.New(viper.New())
+	// the prefix is joined with a single underscore, so it ends with one to be separated with `__`
+	e.SetEnvPrefix("APP_")
+	e.SetEnvKeyReplacer(strings.NewReplacer(".", "__"))
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, 1, c.Bar.Baz)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+	assert.Equal(t, map[string]string{"ok": "yes"}, c.Labels)
+}
+
+func TestReplacerOnViperIsHonored(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_DB__HOST":   "nested",
+		"APP_DB_HOST":    "flat",
+		"APP_TAGS__1":    "b",
+		"APP_TAGS_0":     "ignored",
+		"APP_LABELS__OK": "yes",
+		"APP_LABELS_NO":  "ignored",
+		"APP_CACHE__TTL": "1m",
+	})()
+
+	var c struct {
+		DB struct {
+			Host string
+		}
+		DBHost string `mapstructure:"db_host"`
+		Tags   []string
+		Labels map[string]string
+	}
+	v := viper.New()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "__"))
+	e := enviper.New(v)
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "nested", c.DB.Host)
+	assert.Equal(t, "flat", c.DBHost)
+	assert.Equal(t, []string{"", "b"}, c.Tags)
+	assert.Equal(t, map[string]string{"ok": "yes"}, c.Labels)
+
+	// the replacer is kept on viper
+	assert.Nil(t, v.BindEnv("cache.ttl"))
+	assert.Equal(t, "1m", v.GetString("cache.ttl"))
+}
+
+func TestReplacerOnViperUsedBeforeEnviper(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_DB__HOST":   "nested",
+		"APP_TAGS__0":    "a",
+		"APP_CACHE__TTL": "1m",
+	})()
+
+	v := viper.New()
+	v.SetEnvPrefix("APP")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "__"))
+	assert.Nil(t, v.BindEnv("cache.ttl"))
+	assert.Equal(t, "1m", v.GetString("cache.ttl"))
+
+	var c struct {
+		DB struct {
+			Host string
+		}
+		Tags []string
+	}
+	e := enviper.New(v)
+	e.SetEnvPrefix("APP")
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "nested", c.DB.Host)
+	assert.Equal(t, []string{"a"}, c.Tags)
+}
+
+func TestPrefixOnViperIsHonored(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_FOO": "x"})()
+
+	var c struct {
+		Foo string
+	}
+	e := enviper.New(viper.New())
+	e.Viper.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "x", c.Foo)
+}
+
+func TestSuffixPrefix(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"FOO_APP":                "foo",
+		"BAR_BAZ_APP":            "1",
+		"PRIMITIVEMAP_TEAM_APP":  "core",
+		"QUXMAP_KEY_QUUUX_APP":   "true",
+		"APP_FOO":                "ignored",
+		"FOOPTR_VALUE_APP_EXTRA": "ignored",
+	})()
+
+	var c Config
+	e := enviper.New(viper.New()).WithSuffixPrefix()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "foo", c.Foo)
+	assert.Equal(t, 1, c.Bar.BAZ)
+	assert.Equal(t, map[string]string{"team": "core"}, c.PrimitiveMap)
+	assert.True(t, c.QuxMap["key"].Quuux)
+	assert.Nil(t, c.FooPtr)
+
+	env, err := e.MarshalEnv(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", env["FOO_APP"])
+	assert.Equal(t, "1", env["BAR_BAZ_APP"])
+}
+
+func TestSuffixPrefixSlices(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"SERVERS_1_HOST_APP":   "second",
+		"SERVERS_1_TLS_CA_APP": "a,b",
+		"TAGS_0_APP":           "x",
+		"UNKNOWN_APP":          "value",
+	})()
+
+	var c SlicesConfig
+	e := enviper.New(viper.New()).WithSuffixPrefix()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	if assert.Len(t, c.Servers, 2) {
+		assert.Equal(t, "second", c.Servers[1].Host)
+		assert.Equal(t, []string{"a", "b"}, c.Servers[1].TLS.CA)
+	}
+	assert.Equal(t, []string{"x"}, c.Tags)
+
+	problems := e.LintEnv(&c)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "UNKNOWN_APP", problems[0].Env)
+	}
+}
+
+func TestDottedEnvPrefix(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"MY_APP_FOO":           "foo",
+		"MY_APP_BAR_BAZ":       "1",
+		"MY_APP_TAGS_1":        "b",
+		"MY_APP_SERVERS_0_URL": "http://a",
+		"MY_APP_MAP_KEY":       "value",
+	})()
+
+	var c struct {
+		Foo string
+		Bar struct {
+			Baz int
+		}
+		Tags    []string
+		Servers []struct {
+			URL string
+		}
+		Map map[string]string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("my.app")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "foo", c.Foo)
+		assert.Equal(t, 1, c.Bar.Baz)
+		assert.Equal(t, []string{"", "b"}, c.Tags)
+		if assert.Len(t, c.Servers, 1) {
+			assert.Equal(t, "http://a", c.Servers[0].URL)
+		}
+		assert.Equal(t, map[string]string{"key": "value"}, c.Map)
+	}
+	assert.Equal(t, []string{
+		"MY_APP_BAR_BAZ",
+		"MY_APP_FOO",
+		"MY_APP_MAP_KEY",
+		"MY_APP_SERVERS_0_URL",
+		"MY_APP_TAGS_1",
+	}, e.ConsumedEnvVars(&c))
+
+	env, err := e.MarshalEnv(&c)
+	if assert.Nil(t, err) {
+		for name := range env {
+			assert.NotContains(t, name, ".")
+		}
+	}
+}
+
+func TestWithEnvPrefixAndReplacer(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TAGS_0":         "a",
+		"APP_TAGS_1":         "b",
+		"APP_DB__HOST":       "db",
+		"APP_SERVERS__0__IP": "10.0.0.1",
+	})()
+
+	var c struct {
+		Tags []string
+		DB   struct {
+			Host string
+		}
+		Servers []struct {
+			IP string
+		}
+	}
+	e := enviper.New(viper.New()).WithEnvPrefix("app")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, []string{"a", "b"}, c.Tags)
+		assert.Equal(t, "", c.DB.Host)
+	}
+
+	e.WithEnvKeyReplacer(strings.NewReplacer(".", "__"))
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "db", c.DB.Host)
+		if assert.Len(t, c.Servers, 1) {
+			assert.Equal(t, "10.0.0.1", c.Servers[0].IP)
+		}
+	}
+}
+
+func TestNoUnsafe(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, imp := range f.Imports {
+			assert.NotEqual(t, `"unsafe"`, imp.Path.Value, file)
+		}
+	}
+}
+
+func TestAlternatingPointerNesting(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_A_B_C_D": "x"})()
+
+	var c struct {
+		A *struct {
+			B struct {
+				C *struct {
+					D string
+				}
+				E *int
+			}
+		}
+		F *struct {
+			G string
+		}
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) && assert.NotNil(t, c.A) && assert.NotNil(t, c.A.B.C) {
+		assert.Equal(t, "x", c.A.B.C.D)
+		// pointers without env variables stay nil
+		assert.Nil(t, c.A.B.E)
+		assert.Nil(t, c.F)
+	}
+}
+
+func TestUnmarshalKey(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+database:
+  host: file.host
+  port: 5432
+  replicas:
+    - host: r0
+  options:
+    sslmode: disable
+cache:
+  host: cache.host
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"MYAPP_DATABASE_HOST":            "env.host",
+		"MYAPP_DATABASE_REPLICAS_1_HOST": "r1",
+		"MYAPP_DATABASE_OPTIONS_TIMEOUT": "5",
+		"MYAPP_HOST":                     "root.host",
+	})()
+
+	type Database struct {
+		Host     string
+		Port     int
+		Replicas []struct{ Host string }
+		Options  map[string]string
+	}
+	var db Database
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("MYAPP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.UnmarshalKey("database", &db)) {
+		assert.Equal(t, "env.host", db.Host)
+		assert.Equal(t, 5432, db.Port)
+		if assert.Len(t, db.Replicas, 2) {
+			assert.Equal(t, "r0", db.Replicas[0].Host)
+			assert.Equal(t, "r1", db.Replicas[1].Host)
+		}
+		assert.Equal(t, map[string]string{"sslmode": "disable", "timeout": "5"}, db.Options)
+	}
+
+	var cache struct{ Host string }
+	if assert.Nil(t, e.UnmarshalKey("cache", &cache)) {
+		assert.Equal(t, "cache.host", cache.Host)
+	}
+}
+
+func TestUnmarshalKeyOnlyInEnv(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"MYAPP_SERVICES_AUTH_URL": "http://auth",
+		"MYAPP_SERVICES_AUTH_TLS": "true",
+	})()
+
+	var auth struct {
+		URL string
+		TLS bool
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("MYAPP")
+
+	if assert.Nil(t, e.UnmarshalKey("services.auth", &auth)) {
+		assert.Equal(t, "http://auth", auth.URL)
+		assert.True(t, auth.TLS)
+	}
+}
@@ -0,0 +1,91 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// UnmarshalExact does the same as Unmarshal, but strictly, e.g. for validating config in CI:
+// keys of config file that don't match any field are reported with mapstructure.Error,
+// when env prefix is set env variables with the prefix that don't match any field are reported too,
+// and so are fields with `required` tag option (e.g. `mapstructure:"db_host,required"`) that aren't set.
+// Explicit zero values, like `port: 0` in config file, are set.
+// Fields with `validate:"required"` tag are required by Unmarshal itself.
+func (e *Enviper) UnmarshalExact(rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	exact := func(c *mapstructure.DecoderConfig) {
+		c.ErrorUnused = true
+	}
+	if err := e.Unmarshal(rawVal, append(opts, exact)...); err != nil {
+		return err
+	}
+
+	var unknown []string
+	for _, p := range e.LintEnv(rawVal) {
+		if p.Reason == unknownEnvReason {
+			unknown = append(unknown, p.Env)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown env variables: %s", strings.Join(unknown, ", "))
+	}
+
+	if missing := e.missingRequired(reflect.ValueOf(rawVal), nil, e.requiredAt); len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("required fields are not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// missingRequired returns paths of required fields that have zero values, required tells whether the field at the path is required
+func (e *Enviper) missingRequired(v reflect.Value, path []string, required func(reflect.StructField, tagOptions, []string) bool) []string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if e.isLeaf(v.Type()) {
+		return nil
+	}
+
+	var missing []string
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			name, opts := parseTag(sf.Tag.Get(e.TagName()))
+			if name == "-" {
+				continue
+			}
+			if e.isSquashed(sf, name, opts) {
+				missing = append(missing, e.missingRequired(v.Field(i), path, required)...)
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fieldPath := appendPath(path, name)
+			if required(sf, opts, fieldPath) && !e.isValueSet(v.Field(i)) {
+				missing = append(missing, strings.Join(fieldPath, "."))
+				continue
+			}
+			missing = append(missing, e.missingRequired(v.Field(i), fieldPath, required)...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			missing = append(missing, e.missingRequired(v.Index(i), appendPath(path, strconv.Itoa(i)), required)...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			missing = append(missing, e.missingRequired(iter.Value(), appendPath(path, fmt.Sprint(valueInterface(iter.Key()))), required)...)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,153 @@
+package enviper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type ExactBase struct {
+	Region string `mapstructure:"region,required"`
+}
+
+type ExactConfig struct {
+	ExactBase `mapstructure:",squash"`
+	DBHost    string `mapstructure:"db_host,required"`
+	Port      int    `mapstructure:"port"`
+	Ignored   string `mapstructure:"-"`
+	Servers   []struct {
+		Name string `mapstructure:"name,required"`
+	} `mapstructure:"servers"`
+}
+
+func exactEnviper(t *testing.T, config string) (*enviper.Enviper, func()) {
+	dir, cleanup := writeConfig(t, config)
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+	return e, cleanup
+}
+
+func TestUnmarshalExact(t *testing.T) {
+	e, cleanup := exactEnviper(t, `
+region: eu
+port: 80
+servers:
+  - name: a
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_DB_HOST": "db"})()
+
+	var c ExactConfig
+	if assert.Nil(t, e.UnmarshalExact(&c)) {
+		assert.Equal(t, "eu", c.Region)
+		assert.Equal(t, "db", c.DBHost)
+		assert.Equal(t, 80, c.Port)
+	}
+}
+
+func TestUnmarshalExactUnknownKeys(t *testing.T) {
+	e, cleanup := exactEnviper(t, `
+region: eu
+db_host: db
+prot: 80
+`)
+	defer cleanup()
+
+	var c ExactConfig
+	err := e.UnmarshalExact(&c)
+	if assert.NotNil(t, err) {
+		if merr, ok := err.(*mapstructure.Error); assert.True(t, ok, err.Error()) {
+			assert.Len(t, merr.Errors, 1)
+			assert.Contains(t, merr.Errors[0], "prot")
+		}
+	}
+
+	// Unmarshal ignores unknown keys
+	assert.Nil(t, e.Unmarshal(&c))
+}
+
+func TestUnmarshalExactUnknownEnv(t *testing.T) {
+	e, cleanup := exactEnviper(t, `
+region: eu
+db_host: db
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_PROT": "80", "APP_DB_HOTS": "x"})()
+
+	var c ExactConfig
+	err := e.UnmarshalExact(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "unknown env variables: APP_DB_HOTS, APP_PROT", err.Error())
+	}
+}
+
+func TestUnmarshalExactRequired(t *testing.T) {
+	e, cleanup := exactEnviper(t, `
+servers:
+  - name: a
+  - {}
+`)
+	defer cleanup()
+
+	var c ExactConfig
+	err := e.UnmarshalExact(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "required fields are not set: db_host, region, servers.1.name", err.Error())
+	}
+
+	// the required option is stripped from the name, so the field is still set by its env variable
+	defer setenv(t, map[string]string{"APP_DB_HOST": "db", "APP_REGION": "eu", "APP_SERVERS_1_NAME": "b"})()
+	c = ExactConfig{}
+	assert.Nil(t, e.UnmarshalExact(&c))
+}
+
+func TestUnmarshalExactRequiredExplicitZero(t *testing.T) {
+	e, cleanup := exactEnviper(t, `
+region: eu
+servers:
+  - name: ""
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_DB_HOST": ""})()
+
+	var c struct {
+		ExactConfig `mapstructure:",squash"`
+		Enabled     bool `mapstructure:"enabled,required"`
+		Workers     int  `mapstructure:"workers,required"`
+	}
+	err := e.UnmarshalExact(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "required fields are not set: db_host, enabled, workers", err.Error())
+	}
+
+	e, cleanup = exactEnviper(t, `
+region: eu
+enabled: false
+servers:
+  - name: ""
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_DB_HOST": "db", "APP_WORKERS": "0"})()
+
+	assert.Nil(t, e.UnmarshalExact(&c))
+}
+
+func TestUnmarshalExactIsSetFunc(t *testing.T) {
+	e, cleanup := exactEnviper(t, `
+region: eu
+db_host: ""
+`)
+	defer cleanup()
+	e.WithIsSetFunc(func(v reflect.Value) bool {
+		return v.Kind() == reflect.String || !v.IsZero()
+	})
+
+	var c ExactConfig
+	assert.Nil(t, e.UnmarshalExact(&c))
+}
@@ -0,0 +1,73 @@
+package enviper
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Sources of values of fields reported by ExplainBindings
+const (
+	// SourceEnv is the source of fields set by env variables
+	SourceEnv = "env"
+	// SourceConfig is the source of fields set by viper, e.g. by config file or by defaults set with viper.SetDefault
+	SourceConfig = "config"
+	// SourceDefault is the source of fields set by neither env variables nor viper, e.g. by values rawVal had before Unmarshal
+	SourceDefault = "default"
+)
+
+// Binding describes the field of the config, it's an entry of ExplainBindings
+type Binding struct {
+	// Key is the lowercased config key of the field, e.g. `db.host` or `servers.0.host`
+	Key string
+	// Env is the name of env variable bound to the field including env prefix
+	Env string
+	// Type is the Go type of the field, pointers are dereferenced
+	Type string
+	// Source is SourceEnv, SourceConfig or SourceDefault, it's empty for fields that are not set
+	Source string
+	// Set reports whether the field is set, see WithIsSetFunc
+	Set bool
+}
+
+// ExplainBindings describes every field of rawVal sorted by keys, e.g. to generate docs of env variables.
+// Elements of slices and entries of maps rawVal has are listed as fields too,
+// so call it after Unmarshal to get sources of values and elements from config file and env variables.
+func (e *Enviper) ExplainBindings(rawVal interface{}) []Binding {
+	bindings := []Binding{}
+	settings := e.Viper.AllSettings()
+	profile := e.profile()
+	sources := map[string]string{}
+	e.walkElements(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		if len(f.path) == 0 || !f.value.IsValid() || f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+			return
+		}
+		b := Binding{
+			Key:  strings.ToLower(strings.Join(f.path, ".")),
+			Env:  e.fieldEnvName(f),
+			Type: f.value.Type().String(),
+			Set:  e.isValueSet(f.value),
+		}
+		switch {
+		case len(e.setCandidates(f, profile)) > 0:
+			b.Source = SourceEnv
+		case getPath(settings, f.path) != nil:
+			b.Source = SourceConfig
+		case f.indexed:
+			// elements of slices set as a whole, e.g. by `MYAPP_TAGS=a,b`, come from the source of the slice
+			for i := len(f.path) - 1; i > 0 && b.Source == ""; i-- {
+				b.Source = sources[strings.Join(f.path[:i], ".")]
+			}
+		}
+		if b.Source == "" && b.Set {
+			b.Source = SourceDefault
+		}
+		sources[strings.Join(f.path, ".")] = b.Source
+		bindings = append(bindings, b)
+	}, allElements)
+
+	sort.SliceStable(bindings, func(i, j int) bool {
+		return bindings[i].Key < bindings[j].Key
+	})
+	return bindings
+}
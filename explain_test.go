@@ -0,0 +1,69 @@
+package enviper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type ExplainConfig struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+	Debug   *bool
+	Tags    []string
+	DB      struct {
+		User string
+	}
+	Servers []struct {
+		Host string
+	}
+	Limits map[string]int
+}
+
+func TestExplainBindings(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+host: file.host
+servers:
+  - host: a
+limits:
+  read: 1
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_PORT":           "8080",
+		"APP_TAGS":           "a,b",
+		"APP_SERVERS_1_HOST": "b",
+		"APP_LIMITS_WRITE":   "2",
+	})()
+
+	v := viper.New()
+	v.SetDefault("db.user", "admin")
+	e := enviper.New(v)
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	c := ExplainConfig{Timeout: 5 * time.Second}
+	if !assert.Nil(t, e.Unmarshal(&c)) {
+		return
+	}
+	assert.Equal(t, []enviper.Binding{
+		{Key: "db.user", Env: "APP_DB_USER", Type: "string", Source: enviper.SourceConfig, Set: true},
+		{Key: "debug", Env: "APP_DEBUG", Type: "bool"},
+		{Key: "host", Env: "APP_HOST", Type: "string", Source: enviper.SourceConfig, Set: true},
+		{Key: "limits.read", Env: "APP_LIMITS_READ", Type: "int", Source: enviper.SourceConfig, Set: true},
+		{Key: "limits.write", Env: "APP_LIMITS_WRITE", Type: "int", Source: enviper.SourceEnv, Set: true},
+		{Key: "port", Env: "APP_PORT", Type: "int", Source: enviper.SourceEnv, Set: true},
+		{Key: "servers", Env: "APP_SERVERS", Type: "[]struct { Host string }", Source: enviper.SourceConfig, Set: true},
+		{Key: "servers.0.host", Env: "APP_SERVERS_0_HOST", Type: "string", Source: enviper.SourceConfig, Set: true},
+		{Key: "servers.1.host", Env: "APP_SERVERS_1_HOST", Type: "string", Source: enviper.SourceEnv, Set: true},
+		{Key: "tags", Env: "APP_TAGS", Type: "[]string", Source: enviper.SourceEnv, Set: true},
+		{Key: "tags.0", Env: "APP_TAGS_0", Type: "string", Source: enviper.SourceEnv, Set: true},
+		{Key: "tags.1", Env: "APP_TAGS_1", Type: "string", Source: enviper.SourceEnv, Set: true},
+		{Key: "timeout", Env: "APP_TIMEOUT", Type: "time.Duration", Source: enviper.SourceDefault, Set: true},
+	}, e.ExplainBindings(&c))
+}
@@ -0,0 +1,32 @@
+package enviper
+
+import "github.com/mitchellh/mapstructure"
+
+// WithFailFast makes Unmarshal return the first error it finds instead of aggregating all of them,
+// e.g. only the first field that can't be decoded or the first field that isn't set.
+// Binding env variables stops at the first error, so the rest of them are not even looked up.
+func (e *Enviper) WithFailFast() *Enviper {
+	e.failFast = true
+	return e
+}
+
+// failed reports whether the walk should stop because of errors found so far
+func (e *Enviper) failed(errs []string) bool {
+	return e.failFast && len(errs) > 0
+}
+
+// firstOnly returns problems as is, or only the first of them in fail fast mode
+func (e *Enviper) firstOnly(problems []string) []string {
+	if e.failFast && len(problems) > 1 {
+		return problems[:1]
+	}
+	return problems
+}
+
+// firstDecodeError returns only the first of errors aggregated by mapstructure in fail fast mode
+func (e *Enviper) firstDecodeError(err error) error {
+	if me, ok := err.(*mapstructure.Error); ok && e.failFast && len(me.Errors) > 1 {
+		return &mapstructure.Error{Errors: me.Errors[:1]}
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package enviper_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type FailFastConfig struct {
+	Port    int
+	Timeout time.Duration
+	Name    string
+}
+
+func TestFailFastDecodeErrors(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_PORT":    "eighty",
+		"APP_TIMEOUT": "forever",
+	})()
+
+	var c FailFastConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "2 error(s) decoding")
+		assert.Contains(t, err.Error(), "'Port'")
+		assert.Contains(t, err.Error(), "'Timeout'")
+	}
+
+	err = e.WithFailFast().Unmarshal(&c)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "1 error(s) decoding")
+		assert.Contains(t, err.Error(), "'Port'")
+		assert.NotContains(t, err.Error(), "'Timeout'")
+	}
+}
+
+func TestFailFastRequireAllFields(t *testing.T) {
+	var c FailFastConfig
+	e := enviper.New(viper.New()).WithRequireAllFields().WithFailFast()
+
+	assert.EqualError(t, e.Unmarshal(&c), "fields are not set: Port")
+}
+
+func TestFailFastFieldReaders(t *testing.T) {
+	var c ReaderConfig
+	var calls []string
+	reader := func(path string) enviper.FieldReader {
+		return func(func(string) (string, bool)) (interface{}, error) {
+			calls = append(calls, path)
+			return nil, errors.New("broken")
+		}
+	}
+	e := enviper.New(viper.New()).
+		RegisterFieldReader("name", reader("name")).
+		RegisterFieldReader("db.dsn", reader("db.dsn")).
+		WithFailFast()
+
+	assert.EqualError(t, e.Unmarshal(&c), "can't read fields: db.dsn: broken")
+	assert.Equal(t, []string{"db.dsn"}, calls)
+}
@@ -0,0 +1,66 @@
+package enviper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// WithBestEffortFileRead makes Unmarshal proceed when config file can't be read or parsed,
+// using whatever viper already has (e.g. configs merged with MergeInConfig or MergeConfigMap) plus env variables.
+// Errors are not returned, but kept as warnings available with FileReadWarnings.
+func (e *Enviper) WithBestEffortFileRead() *Enviper {
+	e.bestEffortFileRead = true
+	return e
+}
+
+// FileReadWarnings returns errors of reading config file that were ignored by the last Unmarshal
+// because of WithBestEffortFileRead
+func (e *Enviper) FileReadWarnings() []error {
+	return e.fileReadWarnings
+}
+
+// WithSkipFileEnv sets the env variable that makes Unmarshal skip reading config file when it's true,
+// e.g. `APP_NO_CONFIG_FILE=1` for images that are sometimes run without the file.
+// The name of env variable is used as is, without env prefix. Config already read by viper is kept.
+func (e *Enviper) WithSkipFileEnv(name string) *Enviper {
+	e.skipFileEnv = name
+	return e
+}
+
+// skipFile reports whether reading config file is skipped by the env variable set with WithSkipFileEnv
+func (e *Enviper) skipFile() (bool, error) {
+	if e.skipFileEnv == "" {
+		return false, nil
+	}
+	val, _ := e.lookupEnv(e.skipFileEnv)
+	raw := strings.ToLower(strings.TrimSpace(val))
+	if raw == "" {
+		return false, nil
+	}
+	skip, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("can't parse %s=%q as bool", e.skipFileEnv, val)
+	}
+	return skip, nil
+}
+
+// readInConfig reads config file, missing file is not an error
+func (e *Enviper) readInConfig() error {
+	e.fileReadWarnings = nil
+	if skip, err := e.skipFile(); skip || err != nil {
+		return err
+	}
+	err := e.Viper.ReadInConfig()
+	switch err.(type) {
+	case nil, viper.ConfigFileNotFoundError:
+		return nil
+	}
+	if e.bestEffortFileRead {
+		e.fileReadWarnings = append(e.fileReadWarnings, err)
+		return nil
+	}
+	return err
+}
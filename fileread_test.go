@@ -0,0 +1,132 @@
+package enviper_test
+
+import (
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const badYAML = `
+db:
+  host: bad
+ port: [
+`
+
+func TestBestEffortFileRead(t *testing.T) {
+	dir, cleanup := writeConfig(t, badYAML)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_DB_PORT": "5433"})()
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	assert.Nil(t, v.MergeConfig(strings.NewReader(`
+db:
+  host: base.host
+  port: 5432
+`)))
+
+	var c struct {
+		DB struct {
+			Host string
+			Port int
+		}
+	}
+	e := enviper.New(v).WithBestEffortFileRead()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "base.host", c.DB.Host)
+	assert.Equal(t, 5433, c.DB.Port)
+	if assert.Len(t, e.FileReadWarnings(), 1) {
+		assert.Contains(t, e.FileReadWarnings()[0].Error(), "While parsing config")
+	}
+}
+
+func TestFileReadErrorWithoutBestEffort(t *testing.T) {
+	dir, cleanup := writeConfig(t, badYAML)
+	defer cleanup()
+
+	var c struct {
+		DB struct {
+			Host string
+		}
+	}
+	e := enviper.New(viper.New())
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.NotNil(t, e.Unmarshal(&c))
+	assert.Empty(t, e.FileReadWarnings())
+}
+
+func TestFileReadWarningsReset(t *testing.T) {
+	dir, cleanup := writeConfig(t, badYAML)
+	defer cleanup()
+
+	var c struct{}
+	e := enviper.New(viper.New()).WithBestEffortFileRead()
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Len(t, e.FileReadWarnings(), 1)
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(dir, "config.yaml"), []byte("db: {}"), 0600))
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Empty(t, e.FileReadWarnings())
+}
+
+func TestSkipFileEnv(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+db:
+  host: file.host
+`)
+	defer cleanup()
+
+	for _, tc := range []struct {
+		skip     string
+		expected string
+	}{
+		{"", "file.host"},
+		{"0", "file.host"},
+		{"false", "file.host"},
+		{"1", ""},
+		{" TRUE ", ""},
+	} {
+		func() {
+			defer setenv(t, map[string]string{"APP_NO_CONFIG_FILE": tc.skip})()
+
+			var c struct {
+				DB struct {
+					Host string
+				}
+			}
+			e := enviper.New(viper.New()).WithSkipFileEnv("APP_NO_CONFIG_FILE")
+			e.SetEnvPrefix("APP")
+			e.AddConfigPath(dir)
+			e.SetConfigName("config")
+			if assert.Nil(t, e.Unmarshal(&c), tc.skip) {
+				assert.Equal(t, tc.expected, c.DB.Host, tc.skip)
+				assert.Equal(t, tc.expected, e.GetString("db.host"), tc.skip)
+			}
+		}()
+	}
+}
+
+func TestSkipFileEnvInvalid(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_NO_CONFIG_FILE": "maybe"})()
+
+	var c struct{ Host string }
+	e := enviper.New(viper.New()).WithSkipFileEnv("APP_NO_CONFIG_FILE")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `can't parse APP_NO_CONFIG_FILE="maybe" as bool`, err.Error())
+	}
+}
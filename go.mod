@@ -3,7 +3,10 @@ module github.com/iamolegga/enviper
 go 1.14
 
 require (
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/mitchellh/mapstructure v1.1.2
+	github.com/pelletier/go-toml v1.2.0
 	github.com/spf13/viper v1.7.0
 	github.com/stretchr/testify v1.3.0
+	gopkg.in/yaml.v2 v2.2.4
 )
@@ -0,0 +1,135 @@
+package enviper
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// hexHook decodes hex strings of `[]byte` fields with `hex` tag option (e.g. `mapstructure:"key,hex"`),
+// e.g. `MYAPP_KEY=deadbeef`, whether they come from env or config file
+func (e *Enviper) hexHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.Map || t.Kind() != reflect.Struct {
+		return data, nil
+	}
+	keys := e.hexKeys(t)
+	if len(keys) == 0 {
+		return data, nil
+	}
+	// elements of lists in config file are maps with interface keys
+	m := reflect.ValueOf(data)
+	out := make(map[string]interface{}, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		k, v := fmt.Sprint(iter.Key().Interface()), iter.Value().Interface()
+		out[k] = v
+		s, ok := v.(string)
+		if !ok || !keys[strings.ToLower(k)] {
+			continue
+		}
+		b, err := hex.DecodeString(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("can't decode hex of %s: %s", k, err)
+		}
+		out[k] = b
+	}
+	return out, nil
+}
+
+// checkHex reports values of fields with `hex` tag option that are not valid hex before they are decoded,
+// with keys and env variables of the fields, e.g. `key.sub (APP_KEY_SUB): invalid hex: ...`,
+// as mapstructure knows nothing about the path of the struct the hook decodes.
+func (e *Enviper) checkHex(settings map[string]interface{}, rawVal interface{}, section []string) error {
+	problems := map[string]string{}
+	elements := func(f field) []int {
+		list, _ := getPath(settings, f.path).([]interface{})
+		indexes := make([]int, len(list))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+	var visit func(f field)
+	visit = func(f field) {
+		if f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+			// keys of maps are not in the value yet, so they are taken from settings
+			for _, key := range settingsKeys(getPath(settings, f.path)) {
+				e.walkElements(f.child(key, reflect.New(f.value.Type().Elem()).Elem()), visit, elements)
+			}
+			return
+		}
+		if !f.opts.has("hex") || f.value.Kind() != reflect.Slice || f.value.Type().Elem().Kind() != reflect.Uint8 {
+			return
+		}
+		s, ok := getPath(settings, f.path).(string)
+		if !ok {
+			return
+		}
+		if _, err := hex.DecodeString(strings.TrimSpace(s)); err != nil {
+			key := strings.Join(f.path, ".")
+			problems[key] = fmt.Sprintf("%s (%s): invalid hex: %s", key, e.fieldEnvName(f), err)
+		}
+	}
+	e.walkElements(field{path: section, env: section, value: reflect.ValueOf(rawVal)}, visit, elements)
+	if len(problems) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(problems))
+	for key := range problems {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	errs := make([]string, len(keys))
+	for i, key := range keys {
+		errs[i] = problems[key]
+	}
+	return errors.New(strings.Join(e.firstOnly(errs), "; "))
+}
+
+// settingsKeys returns sorted keys of the map of settings
+func settingsKeys(node interface{}) []string {
+	var keys []string
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k := range n {
+			keys = append(keys, k)
+		}
+	case map[interface{}]interface{}:
+		for k := range n {
+			keys = append(keys, fmt.Sprint(k))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hexKeys returns lowercased keys of `[]byte` fields of the struct with `hex` tag option, squashed fields are flattened
+func (e *Enviper) hexKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" {
+			continue
+		}
+		if e.isSquashed(sf, name, opts) {
+			if ft := sf.Type; ft.Kind() == reflect.Struct {
+				for k := range e.hexKeys(ft) {
+					keys[k] = true
+				}
+			}
+			continue
+		}
+		if !opts.has("hex") || sf.Type.Kind() != reflect.Slice || sf.Type.Elem().Kind() != reflect.Uint8 {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		keys[strings.ToLower(name)] = true
+	}
+	return keys
+}
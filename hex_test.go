@@ -0,0 +1,101 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type HexConfig struct {
+	Key   []byte `mapstructure:"key,hex"`
+	Salt  []byte `mapstructure:",hex"`
+	Peers []struct {
+		Secret []byte `mapstructure:"secret,hex"`
+	}
+}
+
+func TestHexBytes(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+salt: "00ff"
+peers:
+  - secret: cafe
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_KEY": "DEADbeef",
+	})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c HexConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, c.Key)
+		assert.Equal(t, []byte{0x00, 0xff}, c.Salt)
+		if assert.Len(t, c.Peers, 1) {
+			assert.Equal(t, []byte{0xca, 0xfe}, c.Peers[0].Secret)
+		}
+	}
+}
+
+func TestHexBytesEmpty(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_KEY": ""})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.Set("salt", "")
+
+	var c HexConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Empty(t, c.Key)
+		assert.Empty(t, c.Salt)
+	}
+}
+
+func TestHexBytesInvalid(t *testing.T) {
+	for _, raw := range []string{"abc", "zz"} {
+		func() {
+			defer setenv(t, map[string]string{"APP_KEY": raw})()
+
+			e := enviper.New(viper.New())
+			e.SetEnvPrefix("APP")
+
+			var c HexConfig
+			err := e.Unmarshal(&c)
+			if assert.NotNil(t, err, raw) {
+				assert.Contains(t, err.Error(), "key (APP_KEY): invalid hex: encoding/hex: ")
+			}
+		}()
+	}
+}
+
+func TestHexBytesInvalidNested(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+peers:
+  - secret: cafe
+  - secret: nothex
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_KEYS_MAIN_KEY": "abc"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c struct {
+		HexConfig `mapstructure:",squash"`
+		Keys      map[string]struct {
+			Key []byte `mapstructure:"key,hex"`
+		}
+	}
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Keys.main.key (APP_KEYS_MAIN_KEY): invalid hex: encoding/hex: odd length hex string; "+
+			"Peers.1.secret (APP_PEERS_1_SECRET): invalid hex: encoding/hex: invalid byte: U+006E 'n'", err.Error())
+	}
+}
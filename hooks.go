@@ -0,0 +1,380 @@
+package enviper
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// StringDecoder converts a string value from config file or env variable to the value of a custom type
+type StringDecoder func(string) (interface{}, error)
+
+// RegisterStringDecoder registers the decoder for the type,
+// so fields of that type are bound to a single env variable and decoded from string with the decoder.
+// It makes it possible to use third-party types without adding dependencies to enviper, e.g. shopspring/decimal:
+//
+//	e.RegisterStringDecoder(reflect.TypeOf(decimal.Decimal{}), func(s string) (interface{}, error) {
+//		return decimal.NewFromString(s)
+//	})
+func (e *Enviper) RegisterStringDecoder(t reflect.Type, decoder StringDecoder) *Enviper {
+	if e.stringDecoders == nil {
+		e.stringDecoders = map[reflect.Type]StringDecoder{}
+	}
+	e.stringDecoders[t] = decoder
+	return e
+}
+
+// GlobalDecodeHooks are decode hooks used by every Enviper, so packages could register hooks in `init()`:
+//
+//	func init() {
+//		enviper.GlobalDecodeHooks = append(enviper.GlobalDecodeHooks, ipHook)
+//	}
+//
+// They run first, followed by hooks registered with WithDecodeHook, the one passed with viper.DecodeHook option
+// and enviper's hooks, that convert values of supported types and split slices.
+// The slice isn't guarded, so it must not be changed concurrently with Unmarshal.
+var GlobalDecodeHooks []mapstructure.DecodeHookFunc
+
+// WithDecodeHook registers decode hooks that are used by every Unmarshal call.
+// They run before enviper's hooks (e.g. the one decoding JSON arrays), in order of registration,
+// followed by the hook passed to the call with viper.DecodeHook option.
+func (e *Enviper) WithDecodeHook(hooks ...mapstructure.DecodeHookFunc) *Enviper {
+	e.userDecodeHooks = append(e.userDecodeHooks, hooks...)
+	return e
+}
+
+// DefaultDecodeHooks returns hooks converting values of common types that are used by Unmarshal unless
+// WithoutDefaultDecodeHooks is set: types implementing json.Unmarshaler or encoding.TextUnmarshaler (e.g. net.IP),
+// url.Values, url.URL, time.Location, bools, time.Duration and time.Time in RFC3339 format.
+func DefaultDecodeHooks() []mapstructure.DecodeHookFunc {
+	return []mapstructure.DecodeHookFunc{
+		jsonUnmarshalerHook,
+		textUnmarshalerHook,
+		stringToURLValuesHook,
+		stringToURLHook,
+		stringToLocationHook,
+		stringToBoolHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+	}
+}
+
+// WithoutDefaultDecodeHooks makes Unmarshal skip DefaultDecodeHooks for full control over decoding of values,
+// hooks registered with WithDecodeHook are used instead, e.g. some of DefaultDecodeHooks.
+// Hooks binding env variables, like the ones splitting slices, and the ones of enabled options are still used.
+func (e *Enviper) WithoutDefaultDecodeHooks() *Enviper {
+	e.withoutDefaultHooks = true
+	return e
+}
+
+// decodeHooks returns hooks that are composed to the decode hook of Unmarshal in that order
+func (e *Enviper) decodeHooks() []mapstructure.DecodeHookFunc {
+	var hooks []mapstructure.DecodeHookFunc
+	if e.secretFiles {
+		hooks = append(hooks, secretFileHook)
+	}
+	if e.globalSquash {
+		hooks = append(hooks, e.squashHook)
+	}
+	hooks = append(hooks,
+		e.wrappersHook,
+		e.hexHook,
+		e.stringDecodersHook(),
+		e.mapKeyParsersHook,
+	)
+	if e.iso8601Durations {
+		hooks = append(hooks, iso8601DurationHook)
+	}
+	if e.clockDurations {
+		hooks = append(hooks, clockDurationHook)
+	}
+	if len(e.durationAliases) > 0 {
+		hooks = append(hooks, e.durationAliasesHook)
+	}
+	if e.unixTimestampUnit > 0 {
+		hooks = append(hooks, e.unixTimestampHook)
+	}
+	if e.defaultTimeLocation != nil {
+		hooks = append(hooks, e.zonelessTimeHook)
+	}
+	if e.decimalComma {
+		hooks = append(hooks, decimalCommaHook)
+	}
+	if !e.withoutDefaultHooks {
+		hooks = append(hooks, DefaultDecodeHooks()...)
+	}
+	if e.trimTrailingSeparator {
+		hooks = append(hooks, trimTrailingSeparatorHook)
+	}
+	if e.recordSeparators {
+		hooks = append(hooks, e.recordsHook)
+	}
+	if e.kvNameField != "" {
+		hooks = append(hooks, e.kvSliceHook)
+	}
+	if e.csvSlices {
+		hooks = append(hooks, e.csvHook)
+	}
+	hooks = append(hooks, jsonArrayHook(e.jsonNumberMode))
+	if e.strictSliceElements {
+		hooks = append(hooks, e.strictSliceElementsHook)
+	}
+	return append(hooks, mapstructure.StringToSliceHookFunc(","), stringToArrayHook)
+}
+
+// stringToArrayHook splits strings by comma for arrays, like the default hook does for slices
+func stringToArrayHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Array {
+		return data, nil
+	}
+	raw := reflect.ValueOf(data).String()
+	if raw == "" {
+		return []string{}, nil
+	}
+	return strings.Split(raw, ","), nil
+}
+
+// SliceDecodeHook returns the decode hook that decodes JSON arrays from strings to slices,
+// e.g. `MYAPP_SERVERS=[{"host":"a"},{"host":"b"}]`, and JSON objects separated by spaces
+// to slices of structs and maps, e.g. `MYAPP_SERVERS={"host":"a b"} {"host":"c"}`.
+// Other strings are left to the default hook, that splits them by comma.
+func SliceDecodeHook() mapstructure.DecodeHookFuncType {
+	return jsonArrayHook(JSONFloat)
+}
+
+// jsonArrayHook does the same as SliceDecodeHook, but decodes numbers according to the mode
+func jsonArrayHook(mode JSONNumberMode) mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t.Kind() != reflect.Slice && t.Kind() != reflect.Array || t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+		raw := strings.TrimSpace(reflect.ValueOf(data).String())
+		if strings.HasPrefix(raw, "{") && hasObjectElements(t) {
+			list, err := unmarshalJSONObjects(raw, mode)
+			if err != nil {
+				return nil, fmt.Errorf("can't parse %q as JSON objects: %s", raw, err)
+			}
+			return list, nil
+		}
+		if !strings.HasPrefix(raw, "[") {
+			return data, nil
+		}
+		var list []interface{}
+		if err := unmarshalJSON(raw, &list, mode); err != nil {
+			return nil, fmt.Errorf("can't parse %q as JSON array: %s", raw, err)
+		}
+		return list, nil
+	}
+}
+
+// hasObjectElements reports whether elements of the slice type are decoded from JSON objects
+func hasObjectElements(t reflect.Type) bool {
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	return et.Kind() == reflect.Struct && et != timeType || et.Kind() == reflect.Map
+}
+
+// WithTrimTrailingSliceSeparator makes a single trailing separator of slices ignored,
+// so `a,b,` and `["a","b",]` are decoded to `[a b]` instead of having a trailing empty element or failing.
+// Other empty elements are meaningful and kept, e.g. `a,,b` is `[a  b]`.
+func (e *Enviper) WithTrimTrailingSliceSeparator() *Enviper {
+	e.trimTrailingSeparator = true
+	return e
+}
+
+var trailingJSONSeparator = regexp.MustCompile(`,\s*]$`)
+
+func trimTrailingSeparatorHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	if strings.HasPrefix(raw, "[") {
+		return trailingJSONSeparator.ReplaceAllString(raw, "]"), nil
+	}
+	return strings.TrimSuffix(raw, ","), nil
+}
+
+// WithStrictSliceElements makes Unmarshal return an error when elements of slices of structs
+// have keys that don't match any field, instead of silently dropping them.
+// It applies to elements from both config file and JSON env variables.
+func (e *Enviper) WithStrictSliceElements() *Enviper {
+	e.strictSliceElements = true
+	return e
+}
+
+func (e *Enviper) strictSliceElementsHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.Slice || t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return data, nil
+	}
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct || e.isLeaf(et) {
+		return data, nil
+	}
+	v := reflect.ValueOf(data)
+	var unknown []string
+	for i := 0; i < v.Len(); i++ {
+		unknown = append(unknown, e.unknownKeys(et, v.Index(i).Interface(), fmt.Sprintf("[%d]", i))...)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown keys of slice elements: %s", strings.Join(unknown, ", "))
+	}
+	return data, nil
+}
+
+// unknownKeys returns keys of the map that don't match fields of the struct type, nested structs are checked too
+func (e *Enviper) unknownKeys(t reflect.Type, data interface{}, prefix string) []string {
+	var m map[string]interface{}
+	switch d := data.(type) {
+	case map[string]interface{}:
+		m = d
+	case map[interface{}]interface{}:
+		m = make(map[string]interface{}, len(d))
+		for k, v := range d {
+			m[fmt.Sprint(k)] = v
+		}
+	default:
+		return nil
+	}
+
+	fields := e.structKeys(t)
+	var unknown []string
+	for k, v := range m {
+		ft, ok := fields[strings.ToLower(k)]
+		if !ok {
+			unknown = append(unknown, prefix+"."+k)
+			continue
+		}
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !e.isLeaf(ft) {
+			unknown = append(unknown, e.unknownKeys(ft, v, prefix+"."+k)...)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// structKeys returns lowercased keys of the struct fields along with their types, squashed fields are flattened
+func (e *Enviper) structKeys(t reflect.Type) map[string]reflect.Type {
+	keys := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" {
+			continue
+		}
+		if e.isSquashed(sf, name, opts) {
+			for k, ft := range e.structKeys(sf.Type) {
+				keys[k] = ft
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		keys[strings.ToLower(name)] = sf.Type
+	}
+	return keys
+}
+
+// stringDecodersHook returns the decode hook that applies registered string decoders
+func (e *Enviper) stringDecodersHook() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+		decoder, ok := e.stringDecoders[t]
+		if !ok {
+			return data, nil
+		}
+		return decoder(reflect.ValueOf(data).String())
+	}
+}
+
+// stringToBoolHook parses booleans case insensitively and ignoring surrounding spaces,
+// so `TRUE`, `True`, `tRuE` and ` true ` are all the same as `true`
+func stringToBoolHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Bool {
+		return data, nil
+	}
+	raw := strings.ToLower(strings.TrimSpace(reflect.ValueOf(data).String()))
+	if raw == "" {
+		return data, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q as bool", data)
+	}
+	return b, nil
+}
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// stringToURLHook parses URLs like `https://example.com/path`
+func stringToURLHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != urlType {
+		return data, nil
+	}
+	u, err := url.Parse(strings.TrimSpace(reflect.ValueOf(data).String()))
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q as URL: %s", data, err)
+	}
+	return *u, nil
+}
+
+var urlValuesType = reflect.TypeOf(url.Values{})
+
+// stringToURLValuesHook parses query strings like `a=1&b=2&a=3` to url.Values keeping repeated keys
+func stringToURLValuesHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != urlValuesType {
+		return data, nil
+	}
+	values, err := url.ParseQuery(strings.TrimSpace(reflect.ValueOf(data).String()))
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q as query string: %s", data, err)
+	}
+	return values, nil
+}
+
+var locationType = reflect.TypeOf(time.Location{})
+
+// stringToLocationHook loads time zones by their names like `America/New_York` or `UTC`
+func stringToLocationHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != locationType && t != reflect.PtrTo(locationType) {
+		return data, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(reflect.ValueOf(data).String()))
+	if err != nil {
+		return nil, fmt.Errorf("can't load time zone %q: %s", data, err)
+	}
+	return loc, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// isLeaf reports whether values of the type are bound to a single env variable
+// even if they are structs, maps or slices
+func (e *Enviper) isLeaf(t reflect.Type) bool {
+	if t == timeType || t == locationType || t == urlValuesType || t == urlType || isJSONUnmarshaler(t) || isTextUnmarshaler(t) {
+		return true
+	}
+	if _, ok := e.wrappers[t]; ok {
+		return true
+	}
+	_, ok := e.stringDecoders[t]
+	return ok
+}
@@ -0,0 +1,614 @@
+package enviper_test
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// Decimal is a stand-in for third-party fixed-precision types like shopspring/decimal
+type Decimal struct {
+	units int64
+	exp   int
+}
+
+func (d Decimal) FromString(s string) (Decimal, error) {
+	parts := strings.SplitN(s, ".", 2)
+	digits := strings.Join(parts, "")
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Decimal{}, errors.New("can't convert " + s + " to decimal")
+	}
+	if len(parts) == 1 {
+		return Decimal{units: units}, nil
+	}
+	return Decimal{units: units, exp: -len(parts[1])}, nil
+}
+
+func decimalDecoder(s string) (interface{}, error) {
+	return Decimal{}.FromString(s)
+}
+
+func TestRegisterStringDecoder(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_PRICE":       "19.99",
+		"APP_PRICEPTR":    "5",
+		"APP_PRICES_1":    "0.5",
+		"APP_ORDER_TOTAL": "100.10",
+	})()
+
+	var c struct {
+		Price    Decimal
+		PricePtr *Decimal
+		Prices   []Decimal
+		Order    struct {
+			Total Decimal
+		}
+	}
+	e := enviper.New(viper.New()).
+		RegisterStringDecoder(reflect.TypeOf(Decimal{}), decimalDecoder)
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, Decimal{units: 1999, exp: -2}, c.Price)
+	assert.Equal(t, &Decimal{units: 5}, c.PricePtr)
+	assert.Equal(t, []Decimal{{}, {units: 5, exp: -1}}, c.Prices)
+	assert.Equal(t, Decimal{units: 10010, exp: -2}, c.Order.Total)
+}
+
+func TestRegisterStringDecoderError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PRICE": "free"})()
+
+	var c struct {
+		Price Decimal
+	}
+	e := enviper.New(viper.New()).
+		RegisterStringDecoder(reflect.TypeOf(Decimal{}), decimalDecoder)
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't convert free to decimal")
+	}
+}
+
+func TestBoolNormalization(t *testing.T) {
+	for raw, expected := range map[string]bool{
+		"true": true, "TRUE": true, "True": true, "tRuE": true, "1": true, "t": true, "T": true, " true ": true,
+		"false": false, "FALSE": false, "False": false, "fAlSe": false, "0": false, "f": false, "F": false,
+	} {
+		func() {
+			defer setenv(t, map[string]string{"APP_FLAG": raw})()
+
+			c := struct {
+				Flag bool
+			}{Flag: !expected}
+			e := enviper.New(viper.New())
+			e.SetEnvPrefix("APP")
+			if assert.Nil(t, e.Unmarshal(&c), raw) {
+				assert.Equal(t, expected, c.Flag, raw)
+			}
+		}()
+	}
+}
+
+func TestBoolNormalizationError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_FLAG": "maybe"})()
+
+	var c struct {
+		Flag bool
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "maybe" as bool`)
+	}
+}
+
+func TestPointersToBool(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+cache: true
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_DEBUG":            "TRUE",
+		"APP_TLS":              "false",
+		"APP_CACHE":            "0",
+		"APP_FEATURES_BETA":    "f",
+		"APP_SERVERS_0_PUBLIC": "true",
+		"APP_SERVERS_1_PUBLIC": "false",
+	})()
+
+	var c struct {
+		Debug    *bool
+		TLS      *bool
+		Cache    *bool
+		Verbose  *bool
+		Features struct {
+			Beta  *bool
+			Alpha *bool
+		}
+		Servers []struct {
+			Public *bool
+		}
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		if assert.NotNil(t, c.Debug) {
+			assert.True(t, *c.Debug)
+		}
+		if assert.NotNil(t, c.TLS) {
+			assert.False(t, *c.TLS)
+		}
+		if assert.NotNil(t, c.Cache) {
+			assert.False(t, *c.Cache)
+		}
+		if assert.NotNil(t, c.Features.Beta) {
+			assert.False(t, *c.Features.Beta)
+		}
+		assert.Nil(t, c.Verbose)
+		assert.Nil(t, c.Features.Alpha)
+		if assert.Len(t, c.Servers, 2) && assert.NotNil(t, c.Servers[0].Public) && assert.NotNil(t, c.Servers[1].Public) {
+			assert.True(t, *c.Servers[0].Public)
+			assert.False(t, *c.Servers[1].Public)
+		}
+	}
+}
+
+func TestPointerToBoolFromFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+debug: false
+`)
+	defer cleanup()
+
+	var c struct {
+		Debug   *bool
+		Verbose *bool
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		if assert.NotNil(t, c.Debug) {
+			assert.False(t, *c.Debug)
+		}
+		assert.Nil(t, c.Verbose)
+	}
+}
+
+func TestPointerToBoolError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_DEBUG": "maybe"})()
+
+	var c struct {
+		Debug *bool
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "maybe" as bool`)
+	}
+}
+
+func TestSliceDecodeHook(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_SERVERS": `[{"host":"a","tls":{"cert":"a.crt"}},{"host":"b"}]`,
+		"APP_PORTS":   "[80, 443]",
+		"APP_TAGS":    "a,b",
+	})()
+
+	var c struct {
+		Servers []Server
+		Ports   []int
+		Tags    []string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	assert.Nil(t, e.Unmarshal(&c))
+	if assert.Len(t, c.Servers, 2) {
+		assert.Equal(t, "a", c.Servers[0].Host)
+		assert.Equal(t, "a.crt", c.Servers[0].TLS.Cert)
+		assert.Equal(t, "b", c.Servers[1].Host)
+	}
+	assert.Equal(t, []int{80, 443}, c.Ports)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+}
+
+func ipHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != reflect.TypeOf(net.IP{}) {
+		return data, nil
+	}
+	ip := net.ParseIP(data.(string))
+	if ip == nil {
+		return nil, errors.New("invalid IP " + data.(string))
+	}
+	return ip, nil
+}
+
+type DecodeHookConfig struct {
+	IP      net.IP
+	Servers []Server
+	Timeout time.Duration
+}
+
+func TestDecodeHookOption(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_IP":      "10.0.0.1",
+		"APP_SERVERS": `[{"host":"a"}]`,
+		"APP_TIMEOUT": "5s",
+	})()
+
+	var c DecodeHookConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c, viper.DecodeHook(ipHook))) {
+		assert.Equal(t, net.ParseIP("10.0.0.1"), c.IP)
+		if assert.Len(t, c.Servers, 1) {
+			assert.Equal(t, "a", c.Servers[0].Host)
+		}
+		assert.Equal(t, 5*time.Second, c.Timeout)
+	}
+}
+
+func TestWithDecodeHook(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_IP":      "ip:10.0.0.1",
+		"APP_SERVERS": `[{"host":"a"}]`,
+	})()
+
+	// hooks run in order, so the prefix is trimmed before the IP is parsed, and shortened after that
+	trimPrefix := func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if s, ok := data.(string); ok {
+			return strings.TrimPrefix(s, "ip:"), nil
+		}
+		return data, nil
+	}
+	to4 := func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if ip, ok := data.(net.IP); ok {
+			return ip.To4(), nil
+		}
+		return data, nil
+	}
+	e := enviper.New(viper.New()).WithDecodeHook(trimPrefix, ipHook)
+	e.SetEnvPrefix("APP")
+
+	// registered hooks are used by every call
+	for i := 0; i < 2; i++ {
+		var c DecodeHookConfig
+		if assert.Nil(t, e.Unmarshal(&c, viper.DecodeHook(to4))) {
+			assert.Equal(t, net.IP{10, 0, 0, 1}, c.IP)
+			assert.Len(t, c.Servers, 1)
+		}
+	}
+}
+
+func TestGlobalDecodeHooks(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_IP": "ip:10.0.0.1"})()
+
+	trimPrefix := func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if s, ok := data.(string); ok {
+			return strings.TrimPrefix(s, "ip:"), nil
+		}
+		return data, nil
+	}
+	defer func(hooks []mapstructure.DecodeHookFunc) {
+		enviper.GlobalDecodeHooks = hooks
+	}(enviper.GlobalDecodeHooks)
+	// global hooks run before the ones of Enviper
+	enviper.GlobalDecodeHooks = append(enviper.GlobalDecodeHooks, trimPrefix)
+
+	var c DecodeHookConfig
+	e := enviper.New(viper.New()).WithDecodeHook(ipHook)
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, net.ParseIP("10.0.0.1"), c.IP)
+	}
+}
+
+func TestWithDecodeHookError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_IP": "localhost"})()
+
+	var c DecodeHookConfig
+	e := enviper.New(viper.New()).WithDecodeHook(ipHook)
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid IP localhost")
+	}
+}
+
+func TestSliceDecodeHookInvalidJSON(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PORTS": "[80,"})()
+
+	var c struct {
+		Ports []int
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "[80," as JSON array`)
+	}
+}
+
+func TestStrictSliceElements(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_SERVERS": `[{"host":"a"},{"host":"b","port":1,"tls":{"cert":"b.crt","pin":"x"}}]`,
+	})()
+
+	var c struct {
+		Servers []Server
+	}
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "b.crt", c.Servers[1].TLS.Cert)
+
+	e = enviper.New(viper.New()).WithStrictSliceElements()
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown keys of slice elements: [1].port, [1].tls.pin")
+	}
+}
+
+func TestTrimTrailingSliceSeparator(t *testing.T) {
+	for raw, expected := range map[string][]string{
+		"a,b,":          {"a", "b"},
+		"a,b":           {"a", "b"},
+		"a,,b,":         {"a", "", "b"},
+		"a,b,,":         {"a", "b", ""},
+		`["a","b",]`:    {"a", "b"},
+		`["a","b" , ] `: {"a", "b"},
+		`["a",""]`:      {"a", ""},
+	} {
+		func() {
+			defer setenv(t, map[string]string{"APP_TAGS": raw})()
+
+			var c struct {
+				Tags []string
+			}
+			e := enviper.New(viper.New()).WithTrimTrailingSliceSeparator()
+			e.SetEnvPrefix("APP")
+			if assert.Nil(t, e.Unmarshal(&c), raw) {
+				assert.Equal(t, expected, c.Tags, raw)
+			}
+		}()
+	}
+}
+
+func TestTrailingSliceSeparatorKeptByDefault(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_TAGS": "a,b,", "APP_PORTS": "[80,]"})()
+
+	var c struct {
+		Tags  []string
+		Ports []int
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "[80,]" as JSON array`)
+	}
+	assert.Equal(t, []string{"a", "b", ""}, c.Tags)
+}
+
+func TestURLValues(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+file:
+  a: ["1", "2"]
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_PARAMS":       "a=1&b=2&a=3",
+		"APP_ENCODED":      "q=hello+world&path%2Fkey=%2Fetc%2Fhosts&empty=",
+		"APP_NESTED_QUERY": "x=y",
+	})()
+
+	var c struct {
+		Params  url.Values
+		Encoded url.Values
+		File    url.Values
+		Nested  struct {
+			Query *url.Values
+		}
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, url.Values{"a": {"1", "3"}, "b": {"2"}}, c.Params)
+	assert.Equal(t, url.Values{"q": {"hello world"}, "path/key": {"/etc/hosts"}, "empty": {""}}, c.Encoded)
+	assert.Equal(t, url.Values{"a": {"1", "2"}}, c.File)
+	assert.Equal(t, &url.Values{"x": {"y"}}, c.Nested.Query)
+	assert.Nil(t, e.VerifyRoundTrip(&c))
+}
+
+func TestURLValuesError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PARAMS": "a=%zz"})()
+
+	var c struct {
+		Params url.Values
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "a=%zz" as query string`)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TZ":        "America/New_York",
+		"APP_SERVER_TZ": "UTC",
+	})()
+
+	var c struct {
+		TZ     *time.Location
+		Server struct {
+			TZ time.Location
+		}
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	if assert.NotNil(t, c.TZ) {
+		assert.Equal(t, "America/New_York", c.TZ.String())
+	}
+	assert.Equal(t, "UTC", c.Server.TZ.String())
+
+	env, err := e.MarshalEnv(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"APP_TZ": "America/New_York", "APP_SERVER_TZ": "UTC"}, env)
+}
+
+func TestLocationError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_TZ": "Mars/Olympus_Mons"})()
+
+	var c struct {
+		TZ *time.Location
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't load time zone "Mars/Olympus_Mons"`)
+	}
+}
+
+// Severity implements encoding.TextUnmarshaler only
+type Severity int
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "low":
+		*s = 1
+	case "high":
+		*s = 2
+	default:
+		return errors.New("unknown severity " + string(text))
+	}
+	return nil
+}
+
+type DefaultHooksConfig struct {
+	IP       net.IP
+	Endpoint url.URL
+	Callback *url.URL
+	Severity Severity
+	Timeout  time.Duration
+	Since    time.Time
+}
+
+func TestDefaultDecodeHooks(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_IP":       "10.0.0.1",
+		"APP_ENDPOINT": "https://api.example.com/v1?x=1",
+		"APP_CALLBACK": "http://localhost:8080/cb",
+		"APP_SEVERITY": "High",
+		"APP_TIMEOUT":  "5s",
+		"APP_SINCE":    "2020-01-02T03:04:05Z",
+	})()
+
+	var c DefaultHooksConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, net.ParseIP("10.0.0.1"), c.IP)
+		assert.Equal(t, "api.example.com", c.Endpoint.Host)
+		assert.Equal(t, "/v1", c.Endpoint.Path)
+		if assert.NotNil(t, c.Callback) {
+			assert.Equal(t, "http://localhost:8080/cb", c.Callback.String())
+		}
+		assert.Equal(t, Severity(2), c.Severity)
+		assert.Equal(t, 5*time.Second, c.Timeout)
+		assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), c.Since)
+	}
+
+	env, err := e.MarshalEnv(&c)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "https://api.example.com/v1?x=1", env["APP_ENDPOINT"])
+	}
+}
+
+func TestDefaultDecodeHooksErrors(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_SEVERITY": "medium",
+		"APP_ENDPOINT": "http://[::1",
+	})()
+
+	var c DefaultHooksConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown severity medium")
+		assert.Contains(t, err.Error(), `can't parse "http://[::1" as URL`)
+	}
+}
+
+func TestWithoutDefaultDecodeHooks(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_IP":      "10.0.0.1",
+		"APP_TIMEOUT": "5s",
+		"APP_SERVERS": `[{"host":"a"}]`,
+	})()
+
+	var c DecodeHookConfig
+	e := enviper.New(viper.New()).WithoutDefaultDecodeHooks()
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "'Timeout'")
+	}
+
+	// hooks are picked one by one, while slices are still decoded by enviper
+	e.WithDecodeHook(ipHook, mapstructure.StringToTimeDurationHookFunc())
+	c = DecodeHookConfig{}
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, net.ParseIP("10.0.0.1"), c.IP)
+		assert.Equal(t, 5*time.Second, c.Timeout)
+		assert.Len(t, c.Servers, 1)
+	}
+}
+
+func TestWithDecodeHookList(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_IP":      "10.0.0.1",
+		"APP_TIMEOUT": "5s",
+	})()
+
+	var c DecodeHookConfig
+	e := enviper.New(viper.New()).
+		WithoutDefaultDecodeHooks().
+		WithDecodeHook(enviper.DefaultDecodeHooks()...)
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, net.ParseIP("10.0.0.1"), c.IP)
+		assert.Equal(t, 5*time.Second, c.Timeout)
+	}
+}
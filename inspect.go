@@ -0,0 +1,32 @@
+package enviper
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ConsumedEnvVars returns sorted names of env variables that are set and would be applied to the config,
+// e.g. for logging overrides on startup. Keys of maps are taken from rawVal and env variables,
+// so call it after Unmarshal to take keys from config file into account.
+func (e *Enviper) ConsumedEnvVars(rawVal interface{}) []string {
+	var names []string
+	consume := func(name string) {
+		if val, ok := e.lookupEnv(name); ok && val != "" {
+			names = append(names, name)
+		}
+	}
+	e.walk(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		if f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+			return
+		}
+		consume(e.fieldEnvName(f))
+		if e.isNumbered(f.value.Type()) {
+			for _, n := range e.envIndexes(f.env) {
+				consume(e.envName(appendPath(f.env, strconv.Itoa(n))))
+			}
+		}
+	})
+	sort.Strings(names)
+	return names
+}
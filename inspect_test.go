@@ -0,0 +1,60 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumedEnvVars(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_NAME":              "app",
+		"APP_PORT":              "",
+		"APP_SERVERS_1_HOST":    "second",
+		"APP_LABELS_ENV":        "prod",
+		"APP_LABELS_TEAM":       "core",
+		"APP_UNKNOWN":           "value",
+		"APP_TAGS_2":            "b",
+		"APP_LIMITS_CPU_MAX":    "4",
+		"APP_LIMITS_MEMORY_MAX": "1024",
+	})()
+
+	c := RoundTripConfig{
+		Labels: map[string]string{"env": "dev"},
+		Limits: map[string]struct{ Max int }{"cpu": {}},
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Equal(t, []string{
+		"APP_LABELS_ENV",
+		"APP_LABELS_TEAM",
+		"APP_LIMITS_CPU_MAX",
+		"APP_LIMITS_MEMORY_MAX",
+		"APP_NAME",
+		"APP_SERVERS_1_HOST",
+		"APP_TAGS_2",
+	}, e.ConsumedEnvVars(&c))
+}
+
+func TestConsumedEnvVarsNumbered(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TAGS_1": "a",
+		"APP_TAGS_3": "b",
+	})()
+
+	var c struct {
+		Tags []string
+	}
+	e := enviper.New(viper.New()).WithNumberedSlices()
+	e.SetEnvPrefix("APP")
+
+	assert.Equal(t, []string{"APP_TAGS_1", "APP_TAGS_3"}, e.ConsumedEnvVars(&c))
+}
+
+func TestConsumedEnvVarsNone(t *testing.T) {
+	var c Config
+	assert.Empty(t, enviper.New(viper.New()).ConsumedEnvVars(&c))
+}
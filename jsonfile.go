@@ -0,0 +1,70 @@
+package enviper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// jsonFileSuffix is the suffix of env variables with paths of JSON files
+const jsonFileSuffix = "_JSONFILE"
+
+// WithSliceFromJSONFile makes every slice field accept the path of JSON file with the whole slice,
+// e.g. `MYAPP_RULES_JSONFILE=/etc/rules.json`, just like fields tagged with `jsonfile` option do
+// (e.g. `mapstructure:"rules,jsonfile"`). The file takes precedence over `MYAPP_RULES`.
+func (e *Enviper) WithSliceFromJSONFile() *Enviper {
+	e.slicesFromJSONFile = true
+	return e
+}
+
+// readJSONFiles collects values of fields read from JSON files to overrides
+func (e *Enviper) readJSONFiles(rawVal interface{}, prev ...string) error {
+	var errs []string
+	e.walk(field{path: prev, env: prev, value: reflect.ValueOf(rawVal)}, func(f field) {
+		if e.failed(errs) || !e.fromJSONFile(f) {
+			return
+		}
+		env := e.jsonFileEnvName(f)
+		path, ok := e.lookupEnv(env)
+		if !ok || path == "" {
+			return
+		}
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", env, err))
+			return
+		}
+		var val interface{}
+		if err := unmarshalJSON(string(raw), &val, e.jsonNumberMode); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: can't parse %s as JSON: %s", env, path, err))
+			return
+		}
+		e.overrides[strings.Join(f.path, ".")] = val
+	})
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("can't read JSON files: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// jsonFileEnvName returns the name of env variable with the path of JSON file of the field
+func (e *Enviper) jsonFileEnvName(f field) string {
+	return e.suffixedEnvName(f, jsonFileSuffix)
+}
+
+// suffixedEnvName returns the name of env variable of the field with the suffix, that is put before env prefix at the end
+func (e *Enviper) suffixedEnvName(f field, suffix string) string {
+	env := e.fieldEnvName(f)
+	if prefix := e.prefixSuffix(); prefix != "" && strings.HasSuffix(env, prefix) {
+		return env[:len(env)-len(prefix)] + suffix + prefix
+	}
+	return env + suffix
+}
+
+// fromJSONFile reports whether the field could be read from JSON file
+func (e *Enviper) fromJSONFile(f field) bool {
+	return f.opts.has("jsonfile") || e.slicesFromJSONFile && f.value.Kind() == reflect.Slice
+}
@@ -0,0 +1,102 @@
+package enviper_test
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type JSONFileConfig struct {
+	Rules []struct {
+		Path  string
+		Allow bool
+	} `mapstructure:"rules,jsonfile"`
+	Tags []string
+}
+
+func writeJSON(t *testing.T, dir, name, content string) string {
+	file := path.Join(dir, name)
+	assert.Nil(t, ioutil.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+func TestJSONFileTag(t *testing.T) {
+	dir, cleanup := writeConfig(t, "tags: [a]")
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_RULES_JSONFILE": writeJSON(t, dir, "rules.json", `[{"path":"/","allow":true},{"path":"/admin"}]`),
+		"APP_RULES":          `[{"path":"/ignored"}]`,
+		"APP_TAGS_JSONFILE":  writeJSON(t, dir, "tags.json", `["ignored"]`),
+	})()
+
+	var c JSONFileConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	if assert.Len(t, c.Rules, 2) {
+		assert.Equal(t, "/", c.Rules[0].Path)
+		assert.True(t, c.Rules[0].Allow)
+		assert.Equal(t, "/admin", c.Rules[1].Path)
+		assert.False(t, c.Rules[1].Allow)
+	}
+	assert.Equal(t, []string{"a"}, c.Tags)
+}
+
+func TestSliceFromJSONFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, "tags: [a]")
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_TAGS_JSONFILE": writeJSON(t, dir, "tags.json", `["b", "c"]`),
+	})()
+
+	var c JSONFileConfig
+	e := enviper.New(viper.New()).WithSliceFromJSONFile()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"b", "c"}, c.Tags)
+}
+
+func TestJSONFileErrors(t *testing.T) {
+	dir, cleanup := writeConfig(t, "")
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_RULES_JSONFILE": path.Join(dir, "missing.json"),
+		"APP_TAGS_JSONFILE":  writeJSON(t, dir, "tags.json", `["b",`),
+	})()
+
+	var c JSONFileConfig
+	e := enviper.New(viper.New()).WithSliceFromJSONFile()
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't read JSON files: APP_RULES_JSONFILE: open ")
+		assert.Contains(t, err.Error(), "APP_TAGS_JSONFILE: can't parse "+path.Join(dir, "tags.json")+" as JSON")
+	}
+}
+
+func TestJSONFileLint(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_RULES_JSONFILE": "/etc/rules.json",
+		"APP_TAGS_JSONFILE":  "/etc/tags.json",
+	})()
+
+	var c JSONFileConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	problems := e.LintEnv(&c)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "APP_TAGS_JSONFILE", problems[0].Env)
+	}
+}
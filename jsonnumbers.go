@@ -0,0 +1,59 @@
+package enviper
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// JSONNumberMode defines how numbers of JSON values are decoded to dynamic values like `interface{}`
+type JSONNumberMode int
+
+const (
+	// JSONFloat decodes numbers to float64 just like encoding/json does. It's the default.
+	JSONFloat JSONNumberMode = iota
+	// JSONNumber decodes numbers to json.Number, so big integers and precision are kept
+	JSONNumber
+)
+
+// WithJSONNumberMode sets how numbers are decoded from JSON arrays of env variables
+// and the document of WithWholeConfigEnv to `interface{}` values, e.g. elements of `[]interface{}`
+// or values of `map[string]interface{}`. Typed fields are decoded from both modes the same way.
+func (e *Enviper) WithJSONNumberMode(mode JSONNumberMode) *Enviper {
+	e.jsonNumberMode = mode
+	return e
+}
+
+// unmarshalJSON does the same as json.Unmarshal, but decodes numbers according to the mode
+func unmarshalJSON(raw string, v interface{}, mode JSONNumberMode) error {
+	if mode != JSONNumber {
+		return json.Unmarshal([]byte(raw), v)
+	}
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// unmarshalJSONObjects decodes JSON objects separated by whitespace, decoding numbers according to the mode.
+// Objects are read by the decoder, so spaces inside of them don't split them.
+func unmarshalJSONObjects(raw string, mode JSONNumberMode) ([]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	if mode == JSONNumber {
+		decoder.UseNumber()
+	}
+	var list []interface{}
+	for decoder.More() {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			return nil, err
+		}
+		list = append(list, obj)
+	}
+	return list, nil
+}
@@ -0,0 +1,57 @@
+package enviper_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type DynamicConfig struct {
+	Values []interface{}
+	Ports  []int
+	Extra  map[string]interface{}
+}
+
+func unmarshalDynamic(t *testing.T, mode enviper.JSONNumberMode) DynamicConfig {
+	defer setenv(t, map[string]string{
+		"APP_VALUES": `[1, 2.5, 12345678901234567890, "s"]`,
+		"APP_PORTS":  `[80, 443]`,
+		"APP_CONFIG": `{"extra": {"id": 9007199254740993}}`,
+	})()
+
+	var c DynamicConfig
+	e := enviper.New(viper.New()).WithJSONNumberMode(mode).WithWholeConfigEnv("APP_CONFIG")
+	e.SetEnvPrefix("APP")
+	assert.Nil(t, e.Unmarshal(&c))
+	return c
+}
+
+func TestJSONNumberModeFloat(t *testing.T) {
+	c := unmarshalDynamic(t, enviper.JSONFloat)
+	assert.Equal(t, []interface{}{float64(1), 2.5, float64(12345678901234567890), "s"}, c.Values)
+	assert.Equal(t, []int{80, 443}, c.Ports)
+	assert.Equal(t, float64(9007199254740992), c.Extra["id"])
+}
+
+func TestJSONNumberModeNumber(t *testing.T) {
+	c := unmarshalDynamic(t, enviper.JSONNumber)
+	assert.Equal(t, []interface{}{json.Number("1"), json.Number("2.5"), json.Number("12345678901234567890"), "s"}, c.Values)
+	assert.Equal(t, []int{80, 443}, c.Ports)
+	assert.Equal(t, json.Number("9007199254740993"), c.Extra["id"])
+}
+
+func TestJSONNumberModeInvalid(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_VALUES": `[1] [2]`})()
+
+	var c DynamicConfig
+	e := enviper.New(viper.New()).WithJSONNumberMode(enviper.JSONNumber)
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "[1] [2]" as JSON array`)
+	}
+}
@@ -0,0 +1,132 @@
+package enviper
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// EnvKeyStyle defines how env variable names are derived from names of fields without tags
+type EnvKeyStyle int
+
+const (
+	// EnvKeyGoName uses the name of the field, e.g. `MaxConns` is `MAXCONNS`. It's the default.
+	EnvKeyGoName EnvKeyStyle = iota
+	// EnvKeyTagName uses the name from `json` tag of the field, falling back to the name of the field
+	EnvKeyTagName
+	// EnvKeySnakeCase uses the name of the field in snake case, e.g. `MaxConns` is `MAX_CONNS`
+	EnvKeySnakeCase
+)
+
+// WithEnvKeyStyle sets how env variable names are derived from fields without tags.
+// Names are upper cased afterwards. It only changes env variable names, keys of the config file are still matched by names of fields.
+func (e *Enviper) WithEnvKeyStyle(style EnvKeyStyle) *Enviper {
+	e.envKeyStyle = style
+	return e
+}
+
+// WithAcronymAwareKeys is the shortcut for WithEnvKeyStyle(EnvKeySnakeCase),
+// so acronyms are separated from words, e.g. `HTTPPort` is `HTTP_PORT`, `URLParser` is `URL_PARSER` and `IDs` is `IDS`.
+func (e *Enviper) WithAcronymAwareKeys() *Enviper {
+	return e.WithEnvKeyStyle(EnvKeySnakeCase)
+}
+
+// WithConfigKeyCase sets the func changing the case of config keys bound to env variables with viper.BindEnv,
+// e.g. `strings.ToLower`, for viper setups that expect keys in specific case. Keys are joined by `.` before.
+// Env variable names are derived from fields independently, so they are upper cased and follow WithEnvKeyStyle as usual.
+func (e *Enviper) WithConfigKeyCase(fn func(key string) string) *Enviper {
+	e.configKeyCase = fn
+	return e
+}
+
+// configKey returns the config key bound to env variable of the field by the path
+func (e *Enviper) configKey(path []string) string {
+	key := strings.Join(path, ".")
+	if e.configKeyCase != nil {
+		return e.configKeyCase(key)
+	}
+	return key
+}
+
+// WithKeyPipeline sets funcs deriving env variable names from keys joined by `.` (e.g. `db.maxConns`), applied in order.
+// It replaces the default pipeline, that replaces `.` with the env key replacer and upper cases the key,
+// so it has to do both, e.g. to split camelCase too:
+//
+//	e.WithKeyPipeline(enviper.SnakeCaseSegments, strings.NewReplacer(".", "_").Replace, strings.ToUpper)
+//
+// The env prefix is passed through the pipeline on its own.
+func (e *Enviper) WithKeyPipeline(pipeline ...func(string) string) *Enviper {
+	e.keyPipeline = pipeline
+	return e
+}
+
+// SnakeCaseSegments converts every segment of the key to lowercased snake case, keeping acronyms together,
+// e.g. `db.maxConns.HTTPPort` is `db.max_conns.http_port`
+func SnakeCaseSegments(key string) string {
+	segments := strings.Split(key, ".")
+	for i, segment := range segments {
+		if w := words(segment); len(w) > 0 {
+			segments[i] = strings.Join(w, "_")
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// envKey returns the env variable name of the key, e.g. `DB_HOST` of `db.host`
+func (e *Enviper) envKey(key string) string {
+	if e.keyPipeline == nil {
+		return e.replacer().Replace(strings.ToUpper(key))
+	}
+	for _, fn := range e.keyPipeline {
+		key = fn(key)
+	}
+	return key
+}
+
+// envSegment returns the segment of env variable name for the field without tag
+func (e *Enviper) envSegment(sf reflect.StructField) string {
+	switch e.envKeyStyle {
+	case EnvKeyTagName:
+		if name, _ := parseTag(sf.Tag.Get("json")); name != "" && name != "-" {
+			return name
+		}
+	case EnvKeySnakeCase:
+		return strings.Join(words(sf.Name), "_")
+	}
+	return sf.Name
+}
+
+// words splits the name of Go identifier to lower cased words, keeping acronyms together,
+// e.g. `HTTPPortV2` is `[http port v2]` and `IDsByURL` is `[ids by url]`
+func words(name string) []string {
+	runes := []rune(name)
+	var result []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := unicode.IsLower(cur)
+		if i+1 < len(runes) {
+			next = unicode.IsLower(runes[i+1])
+		}
+		lowerToUpper := !unicode.IsUpper(prev) && prev != '_' && unicode.IsUpper(cur)
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && next && !isPlural(runes, i)
+		if lowerToUpper || acronymEnd || cur == '_' {
+			if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+				result = append(result, strings.ToLower(word))
+			}
+			start = i
+		}
+	}
+	if word := strings.Trim(string(runes[start:]), "_"); word != "" {
+		result = append(result, strings.ToLower(word))
+	}
+	return result
+}
+
+// isPlural reports whether the acronym ending at i is followed by the plural `s`, e.g. `IDs`
+func isPlural(runes []rune, i int) bool {
+	if i+1 >= len(runes) || runes[i+1] != 's' {
+		return false
+	}
+	return i+2 == len(runes) || !unicode.IsLower(runes[i+2])
+}
@@ -0,0 +1,175 @@
+package enviper_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type KeyStyleConfig struct {
+	MaxConns int    `json:"max_connections"`
+	HTTPPort int    `json:"port"`
+	Tagged   string `mapstructure:"tagged_name" json:"ignored"`
+	DB       struct {
+		UserName string
+	}
+}
+
+func TestEnvKeyStyle(t *testing.T) {
+	for style, env := range map[enviper.EnvKeyStyle]map[string]string{
+		enviper.EnvKeyGoName: {
+			"APP_MAXCONNS":    "10",
+			"APP_HTTPPORT":    "8080",
+			"APP_TAGGED_NAME": "tagged",
+			"APP_DB_USERNAME": "admin",
+		},
+		enviper.EnvKeyTagName: {
+			"APP_MAX_CONNECTIONS": "10",
+			"APP_PORT":            "8080",
+			"APP_TAGGED_NAME":     "tagged",
+			"APP_DB_USERNAME":     "admin",
+		},
+		enviper.EnvKeySnakeCase: {
+			"APP_MAX_CONNS":    "10",
+			"APP_HTTP_PORT":    "8080",
+			"APP_TAGGED_NAME":  "tagged",
+			"APP_DB_USER_NAME": "admin",
+		},
+	} {
+		func() {
+			defer setenv(t, env)()
+
+			var c KeyStyleConfig
+			e := enviper.New(viper.New()).WithEnvKeyStyle(style)
+			e.SetEnvPrefix("APP")
+
+			assert.Nil(t, e.Unmarshal(&c), style)
+			assert.Equal(t, 10, c.MaxConns, style)
+			assert.Equal(t, 8080, c.HTTPPort, style)
+			assert.Equal(t, "tagged", c.Tagged, style)
+			assert.Equal(t, "admin", c.DB.UserName, style)
+
+			marshaled, err := e.MarshalEnv(&c)
+			assert.Nil(t, err, style)
+			assert.Equal(t, env, marshaled, style)
+		}()
+	}
+}
+
+func TestEnvKeyStyleKeepsFileKeys(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+maxconns: 5
+httpport: 80
+db:
+  username: file
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_HTTP_PORT": "8080",
+		"APP_HTTPPORT":  "9090",
+	})()
+
+	var c KeyStyleConfig
+	e := enviper.New(viper.New()).WithEnvKeyStyle(enviper.EnvKeySnakeCase)
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, 5, c.MaxConns)
+	assert.Equal(t, 8080, c.HTTPPort)
+	assert.Equal(t, "file", c.DB.UserName)
+	assert.Equal(t, []string{"APP_HTTP_PORT"}, e.ConsumedEnvVars(&c))
+}
+
+func TestAcronymAwareKeys(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_HTTP_PORT":   "8080",
+		"APP_URL_PARSER":  "strict",
+		"APP_IDS":         "1,2",
+		"APP_USER_IDS_V2": "3",
+		"APP_HTTPPORT":    "9090",
+	})()
+
+	var c struct {
+		HTTPPort  int
+		URLParser string
+		IDs       []int
+		UserIDsV2 []int
+	}
+	e := enviper.New(viper.New()).WithAcronymAwareKeys()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, 8080, c.HTTPPort)
+	assert.Equal(t, "strict", c.URLParser)
+	assert.Equal(t, []int{1, 2}, c.IDs)
+	assert.Equal(t, []int{3}, c.UserIDsV2)
+}
+
+func TestConfigKeyCase(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_MAX_CONNS":    "10",
+		"APP_TAGGED_NAME":  "tagged",
+		"APP_DB_USER_NAME": "admin",
+	})()
+
+	var keys []string
+	e := enviper.New(viper.New()).WithAcronymAwareKeys().WithConfigKeyCase(func(key string) string {
+		keys = append(keys, key)
+		return strings.ToLower(key)
+	})
+	e.SetEnvPrefix("APP")
+
+	var c KeyStyleConfig
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, 10, c.MaxConns)
+	assert.Equal(t, "tagged", c.Tagged)
+	assert.Equal(t, "admin", c.DB.UserName)
+	assert.Contains(t, keys, "DB.UserName")
+	// lower cased keys are bound to upper cased env variables
+	assert.Equal(t, "admin", e.Get("db.username"))
+	assert.Equal(t, 10, e.GetInt("maxconns"))
+}
+
+func TestKeyPipeline(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"MY_APP_MAX_CONNS":            "10",
+		"MY_APP_HTTP_PORT":            "8080",
+		"MY_APP_TAGGED_NAME":          "tagged",
+		"MY_APP_DB_USER_NAME":         "admin",
+		"MY_APP_UPSTREAMS_0_BASE_URL": "http://a",
+		"MY_APP_LIMITS_READ_REQUESTS": "5",
+		"MY_APP_LIMITS_MAX_CONNS":     "7",
+	})()
+
+	var c struct {
+		KeyStyleConfig `mapstructure:",squash"`
+		Upstreams      []struct {
+			BaseURL string
+		}
+		Limits map[string]int
+	}
+	e := enviper.New(viper.New()).
+		WithKeyPipeline(enviper.SnakeCaseSegments, strings.NewReplacer(".", "_").Replace, strings.ToUpper)
+	e.SetEnvPrefix("myApp")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, 10, c.MaxConns)
+		assert.Equal(t, 8080, c.HTTPPort)
+		assert.Equal(t, "tagged", c.Tagged)
+		assert.Equal(t, "admin", c.DB.UserName)
+		if assert.Len(t, c.Upstreams, 1) {
+			assert.Equal(t, "http://a", c.Upstreams[0].BaseURL)
+		}
+		assert.Equal(t, map[string]int{"read_requests": 5, "max_conns": 7}, c.Limits)
+	}
+}
+
+func TestSnakeCaseSegments(t *testing.T) {
+	assert.Equal(t, "db.max_conns.http_port", enviper.SnakeCaseSegments("db.maxConns.HTTPPort"))
+	assert.Equal(t, "servers.0.base_url", enviper.SnakeCaseSegments("Servers.0.BaseURL"))
+}
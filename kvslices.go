@@ -0,0 +1,70 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithKVStructSlices makes slices of structs with both fields accept space separated `key=value` pairs,
+// e.g. `MYAPP_HEADERS='Accept=text/html X-Id=1'` is `[]Header{{Name: "Accept", Value: "text/html"}, {Name: "X-Id", Value: "1"}}`
+// with `WithKVStructSlices("Name", "Value")`. Fields are named as in Go, values are split by the first `=`,
+// so `A=` has empty value and `A=b=c` has value `b=c`, while pairs without `=` are errors.
+// JSON arrays are still accepted.
+func (e *Enviper) WithKVStructSlices(nameField, valueField string) *Enviper {
+	e.kvNameField, e.kvValueField = nameField, valueField
+	return e
+}
+
+// kvSliceHook parses `key=value` pairs of slices of structs with the fields set by WithKVStructSlices
+func (e *Enviper) kvSliceHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Slice {
+		return data, nil
+	}
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct || e.isLeaf(et) {
+		return data, nil
+	}
+	nameKey, ok := e.fieldKey(et, e.kvNameField)
+	if !ok {
+		return data, nil
+	}
+	valueKey, ok := e.fieldKey(et, e.kvValueField)
+	if !ok {
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	if strings.HasPrefix(raw, "[") || strings.HasPrefix(raw, "{") || strings.Contains(raw, recordSeparator) || strings.Contains(raw, unitSeparator) {
+		return data, nil
+	}
+
+	pairs := strings.Fields(raw)
+	list := make([]interface{}, 0, len(pairs))
+	for _, pair := range pairs {
+		i := strings.Index(pair, "=")
+		if i == -1 {
+			return nil, fmt.Errorf("can't parse %q as key=value pair", pair)
+		}
+		list = append(list, map[string]interface{}{nameKey: pair[:i], valueKey: pair[i+1:]})
+	}
+	return list, nil
+}
+
+// fieldKey returns the config key of the direct field of the struct by its Go name
+func (e *Enviper) fieldKey(t reflect.Type, fieldName string) (string, bool) {
+	sf, ok := t.FieldByName(fieldName)
+	if !ok || len(sf.Index) > 1 {
+		return "", false
+	}
+	name, _ := parseTag(sf.Tag.Get(e.TagName()))
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return sf.Name, true
+	}
+	return name, true
+}
@@ -0,0 +1,68 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type Header struct {
+	Name  string `mapstructure:"key"`
+	Value string
+}
+
+type KVConfig struct {
+	Headers []Header
+	Params  []*Header
+	Servers []struct {
+		Host string
+	}
+}
+
+func TestKVStructSlices(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_HEADERS":        "Accept=text/html  X-Empty= Auth=a=b",
+		"APP_PARAMS":         `[{"key":"a","value":"1"}]`,
+		"APP_SERVERS_0_HOST": "localhost",
+	})()
+
+	var c KVConfig
+	e := enviper.New(viper.New()).WithKVStructSlices("Name", "Value")
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, []Header{
+			{Name: "Accept", Value: "text/html"},
+			{Name: "X-Empty"},
+			{Name: "Auth", Value: "a=b"},
+		}, c.Headers)
+		assert.Equal(t, []*Header{{Name: "a", Value: "1"}}, c.Params)
+		if assert.Len(t, c.Servers, 1) {
+			assert.Equal(t, "localhost", c.Servers[0].Host)
+		}
+	}
+}
+
+func TestKVStructSlicesPointers(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PARAMS": "a=1 b=2"})()
+
+	var c KVConfig
+	e := enviper.New(viper.New()).WithKVStructSlices("Name", "Value")
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, []*Header{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}, c.Params)
+	}
+}
+
+func TestKVStructSlicesMissingSeparator(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_HEADERS": "A=1 B"})()
+
+	var c KVConfig
+	e := enviper.New(viper.New()).WithKVStructSlices("Name", "Value")
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "B" as key=value pair`)
+	}
+}
@@ -0,0 +1,115 @@
+package enviper
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Layer is a source of config values, that are resolved by Resolve in the order of layers
+type Layer interface {
+	// Settings returns values by their config keys, e.g. `db.host` or `servers.0.port`.
+	// Keys that are not set must be missing, so they don't override values of previous layers.
+	Settings(e *Enviper, rawVal interface{}) (map[string]interface{}, error)
+}
+
+// LayerFunc is the func implementing Layer, e.g. for values of command line flags
+type LayerFunc func(e *Enviper, rawVal interface{}) (map[string]interface{}, error)
+
+// Settings calls the func
+func (l LayerFunc) Settings(e *Enviper, rawVal interface{}) (map[string]interface{}, error) {
+	return l(e, rawVal)
+}
+
+// Resolve unmarshals values of layers to rawVal, values of every layer override values of previous ones:
+//
+//	e.Resolve([]enviper.Layer{
+//		enviper.DefaultsLayer(defaultConfig),
+//		enviper.FileLayer("config.yaml"),
+//		enviper.EnvLayer(),
+//	}, &config)
+//
+// Unlike Unmarshal, values set with viper are not used, only the layers.
+func (e *Enviper) Resolve(layers []Layer, rawVal interface{}) error {
+	settings := map[string]interface{}{}
+	for _, l := range layers {
+		values, err := l.Settings(e, rawVal)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		// parents are set before their children, so children are not overridden
+		sort.Strings(keys)
+		for _, key := range keys {
+			settings = setPath(settings, strings.Split(key, "."), values[key]).(map[string]interface{})
+		}
+	}
+	if err := e.checkHex(settings, rawVal, nil); err != nil {
+		return err
+	}
+	e.provided = settings
+	if err := decode(settings, e.decoderConfig(rawVal)); err != nil {
+		return err
+	}
+	if e.setterBinding {
+		if err := e.applySetters(reflect.ValueOf(rawVal), settings); err != nil {
+			return err
+		}
+	}
+	return e.finish(rawVal)
+}
+
+// DefaultsLayer returns the layer of non-zero values of fields of defaults, that is a config struct
+func DefaultsLayer(defaults interface{}) Layer {
+	return LayerFunc(func(e *Enviper, _ interface{}) (map[string]interface{}, error) {
+		values := map[string]interface{}{}
+		e.walkElements(field{value: reflect.ValueOf(defaults)}, func(f field) {
+			if kind := f.value.Kind(); (kind == reflect.Map || kind == reflect.Slice) && !e.isLeaf(f.value.Type()) {
+				return
+			}
+			if !f.indexed && !e.isValueSet(f.value) {
+				return
+			}
+			values[strings.Join(f.path, ".")] = valueInterface(f.value)
+		}, allElements)
+		return values, nil
+	})
+}
+
+// FileLayer returns the layer of values of the config file, its format is taken from its extension
+func FileLayer(path string) Layer {
+	return LayerFunc(func(*Enviper, interface{}) (map[string]interface{}, error) {
+		v := viper.New()
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, err
+		}
+		values := map[string]interface{}{}
+		for _, key := range v.AllKeys() {
+			values[key] = v.Get(key)
+		}
+		return values, nil
+	})
+}
+
+// EnvLayer returns the layer of values of env variables bound to fields of the config,
+// including elements of slices and keys of maps that are set by env variables only
+func EnvLayer() Layer {
+	return LayerFunc(func(e *Enviper, rawVal interface{}) (map[string]interface{}, error) {
+		values := map[string]interface{}{}
+		e.walk(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+			if f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+				return
+			}
+			if val, ok := e.lookupEnv(e.fieldEnvName(f)); ok && val != "" {
+				values[strings.Join(f.path, ".")] = val
+			}
+		})
+		return values, nil
+	})
+}
@@ -0,0 +1,91 @@
+package enviper_test
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type LayersConfig struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+	Servers []struct {
+		Host string
+	}
+}
+
+func TestResolveLayers(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+host: file
+port: 8080
+servers:
+  - host: a
+  - host: b
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_HOST":           "env",
+		"APP_SERVERS_1_HOST": "y",
+	})()
+
+	defaults := LayersConfig{Host: "default", Timeout: 5 * time.Second}
+	file := path.Join(dir, "config.yaml")
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	var c LayersConfig
+	if assert.Nil(t, e.Resolve([]enviper.Layer{
+		enviper.DefaultsLayer(defaults),
+		enviper.FileLayer(file),
+		enviper.EnvLayer(),
+	}, &c)) {
+		assert.Equal(t, "env", c.Host)
+		assert.Equal(t, 8080, c.Port)
+		assert.Equal(t, 5*time.Second, c.Timeout)
+		if assert.Len(t, c.Servers, 2) {
+			assert.Equal(t, "a", c.Servers[0].Host)
+			assert.Equal(t, "y", c.Servers[1].Host)
+		}
+	}
+
+	// the file wins when it's the last layer
+	c = LayersConfig{}
+	if assert.Nil(t, e.Resolve([]enviper.Layer{
+		enviper.EnvLayer(),
+		enviper.FileLayer(file),
+		enviper.DefaultsLayer(defaults),
+	}, &c)) {
+		assert.Equal(t, "default", c.Host)
+		assert.Equal(t, 8080, c.Port)
+		if assert.Len(t, c.Servers, 2) {
+			assert.Equal(t, "b", c.Servers[1].Host)
+		}
+	}
+}
+
+func TestResolveLayerFunc(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PORT": "1"})()
+
+	flags := enviper.LayerFunc(func(*enviper.Enviper, interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"port": 2, "timeout": "1m"}, nil
+	})
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	var c LayersConfig
+	if assert.Nil(t, e.Resolve([]enviper.Layer{enviper.EnvLayer(), flags}, &c)) {
+		assert.Equal(t, 2, c.Port)
+		assert.Equal(t, time.Minute, c.Timeout)
+	}
+}
+
+func TestResolveLayerError(t *testing.T) {
+	var c LayersConfig
+	err := enviper.New(viper.New()).Resolve([]enviper.Layer{enviper.FileLayer("/nonexistent/config.yaml")}, &c)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,131 @@
+package enviper
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EnvProblem describes an env variable that doesn't fit the config
+type EnvProblem struct {
+	// Env is the name of env variable
+	Env string
+	// Reason describes what is wrong with the env variable
+	Reason string
+	// Key is the config key the env variable is bound to,
+	// or the closest known key in case the env variable is unknown
+	Key string
+}
+
+const unknownEnvReason = "unknown env variable"
+
+// LintEnv checks env variables against the config structure without reading config file or binding anything.
+// It reports values that can't be decoded to the type of their fields
+// and, when env prefix is set, env variables with the prefix that don't match any field.
+func (e *Enviper) LintEnv(rawVal interface{}) []EnvProblem {
+	known := map[string]string{}
+	// env variables nested in these env paths are dynamic, e.g. keys of maps
+	var dynamic [][]string
+	var problems []EnvProblem
+	profile := e.profile()
+
+	e.walk(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		env := e.fieldEnvName(f)
+		key := strings.Join(f.path, ".")
+		if f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+			dynamic = append(dynamic, f.env)
+			return
+		}
+		if e.isNumbered(f.value.Type()) {
+			dynamic = append(dynamic, f.env)
+		}
+		known[env] = key
+		for _, name := range f.envNames {
+			known[name] = key
+		}
+		if profile != "" {
+			known[e.profileEnvName(profile, f.env)] = key
+		}
+		if e.fromJSONFile(f) {
+			known[e.jsonFileEnvName(f)] = key
+		}
+		if e.secretFiles {
+			known[e.suffixedEnvName(f, secretFileSuffix)] = key
+		}
+		val, ok := e.lookupEnv(env)
+		if !ok || val == "" {
+			return
+		}
+		out := reflect.New(f.value.Type())
+		if err := decode(val, e.decoderConfig(out.Interface())); err != nil {
+			problems = append(problems, EnvProblem{Env: env, Reason: err.Error(), Key: key})
+		}
+	})
+
+	if e.envPrefix != "" {
+	envs:
+		for _, kv := range e.environ() {
+			env := kv[:strings.Index(kv, "=")]
+			if _, ok := e.envRest(env, nil); !ok {
+				continue
+			}
+			if _, ok := known[env]; ok || env == e.profileEnv || env == e.skipFileEnv {
+				continue
+			}
+			for _, path := range dynamic {
+				if _, ok := e.envRest(env, path); ok {
+					continue envs
+				}
+			}
+			problems = append(problems, EnvProblem{Env: env, Reason: unknownEnvReason, Key: closest(env, known)})
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Env < problems[j].Env
+	})
+	return problems
+}
+
+// closest returns the key of the env variable with the least edit distance to env
+func closest(env string, known map[string]string) string {
+	best, bestDistance := "", -1
+	for name, key := range known {
+		d := distance(env, name)
+		if bestDistance == -1 || d < bestDistance || d == bestDistance && key < best {
+			best, bestDistance = key, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,61 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintEnv(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"LINT_FOOO":              "typo",
+		"LINT_BAR_BAZ":           "notanumber",
+		"LINT_QUUUX":             "true",
+		"LINT_TAGS":              "a,b",
+		"LINT_SERVERS_0_HOST":    "localhost",
+		"LINT_SERVERS_0_TLS_KEX": "typo",
+		"LINT_QUXMAP_KEY1_QUUUX": "true",
+	})()
+
+	v := viper.New()
+	e := enviper.New(v)
+	e.SetEnvPrefix("LINT")
+
+	var c struct {
+		Config  `mapstructure:",squash"`
+		Servers []Server
+		Tags    []string
+	}
+	problems := e.LintEnv(&c)
+
+	if assert.Len(t, problems, 3) {
+		assert.Equal(t, "LINT_BAR_BAZ", problems[0].Env)
+		assert.Equal(t, "bar.baz", problems[0].Key)
+		assert.Contains(t, problems[0].Reason, "notanumber")
+
+		assert.Equal(t, enviper.EnvProblem{Env: "LINT_FOOO", Reason: "unknown env variable", Key: "Foo"}, problems[1])
+		assert.Equal(t, enviper.EnvProblem{Env: "LINT_SERVERS_0_TLS_KEX", Reason: "unknown env variable", Key: "Servers.0.TLS.Key"}, problems[2])
+	}
+
+	// nothing is bound or changed
+	assert.Empty(t, v.AllKeys())
+	assert.Equal(t, "", c.Foo)
+}
+
+func TestLintEnvWithoutPrefix(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"FOOO":    "typo",
+		"BAR_BAZ": "1",
+		"QUUUX":   "maybe",
+	})()
+
+	var c Config
+	problems := enviper.New(viper.New()).LintEnv(c)
+
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "QUUUX", problems[0].Env)
+		assert.Equal(t, "Quuux", problems[0].Key)
+	}
+}
@@ -0,0 +1,87 @@
+package enviper
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// WithMapKeyParser registers the parser of map keys of the type, that isn't a string, e.g. `map[Point]string`.
+// Keys of such maps are taken from env variables (e.g. `MYAPP_GRID_1X2` is the key parsed from `1x2`)
+// and config file, and parsed with the parser. Without the parser such maps are not bound to env variables at all.
+// The parser is applied to values of the type decoded from strings as well.
+func (e *Enviper) WithMapKeyParser(t reflect.Type, parser func(string) (interface{}, error)) *Enviper {
+	if e.mapKeyParsers == nil {
+		e.mapKeyParsers = map[reflect.Type]StringDecoder{}
+	}
+	e.mapKeyParsers[t] = parser
+	return e
+}
+
+// mapKeyParsersHook applies registered map key parsers
+func (e *Enviper) mapKeyParsersHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String {
+		return data, nil
+	}
+	parser, ok := e.mapKeyParsers[t]
+	if !ok {
+		return data, nil
+	}
+	return parser(reflect.ValueOf(data).String())
+}
+
+// WithMapKeysEnv makes keys of maps listed by the `KEYS` env variable of the map,
+// e.g. `MYAPP_SERVERS_KEYS="web api"` creates `web` and `api` entries of `map[string]*Server`
+// bound to `MYAPP_SERVERS_WEB_HOST` and so on, instead of scanning env for them.
+// Keys are separated by spaces or commas and kept as is, entries from config file are kept too.
+// Maps without the `KEYS` env variable are still scanned.
+func (e *Enviper) WithMapKeysEnv() *Enviper {
+	e.mapKeysEnv = true
+	return e
+}
+
+// listedMapKeys returns the keys of the map listed by its `KEYS` env variable, if any
+func (e *Enviper) listedMapKeys(f field) ([]string, bool) {
+	if !e.mapKeysEnv {
+		return nil, false
+	}
+	val, ok := e.lookupEnv(e.envName(appendPath(f.env, "keys")))
+	if !ok || strings.TrimSpace(val) == "" {
+		return nil, false
+	}
+	seen := map[string]bool{}
+	var keys []string
+	for _, key := range strings.FieldsFunc(val, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, true
+}
+
+// createMapEntries creates entries of the map listed by its `KEYS` env variable, that are missing in the map,
+// so they exist even when none of their fields is set. Unlike overrides, they never replace values set by viper.
+func (e *Enviper) createMapEntries(f field) {
+	keys, ok := e.listedMapKeys(f)
+	if !ok || f.value.Type().Key().Kind() != reflect.String {
+		return
+	}
+	et := f.value.Type().Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	var empty interface{} = map[string]interface{}{}
+	if kind := et.Kind(); kind != reflect.Struct && kind != reflect.Map || e.isLeaf(et) {
+		empty = reflect.Zero(et).Interface()
+	}
+	for _, key := range keys {
+		if !f.value.MapIndex(reflect.ValueOf(key).Convert(f.value.Type().Key())).IsValid() {
+			e.mapEntries[strings.Join(appendPath(f.path, key), ".")] = empty
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package enviper_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type Point struct {
+	X, Y int
+}
+
+func parsePoint(s string) (interface{}, error) {
+	var p Point
+	if _, err := fmt.Sscanf(s, "%dx%d", &p.X, &p.Y); err != nil {
+		return nil, fmt.Errorf("can't parse %q as point", s)
+	}
+	return p, nil
+}
+
+type GridConfig struct {
+	Name string
+	Grid map[Point]string
+}
+
+func TestMapWithStructKeysSkipped(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_NAME":     "grid",
+		"APP_GRID_1X2": "val",
+	})()
+
+	var c GridConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "grid", c.Name)
+	assert.Nil(t, c.Grid)
+	assert.Equal(t, []string{"APP_NAME"}, e.ConsumedEnvVars(&c))
+	assert.Empty(t, e.LintEnv(&c))
+}
+
+func TestMapKeyParser(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+grid:
+  "0x0": origin
+  "1x2": file
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_GRID_1X2": "env",
+		"APP_GRID_3X4": "env only",
+	})()
+
+	var c GridConfig
+	e := enviper.New(viper.New()).WithMapKeyParser(reflect.TypeOf(Point{}), parsePoint)
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, map[Point]string{
+		{0, 0}: "origin",
+		{1, 2}: "env",
+		{3, 4}: "env only",
+	}, c.Grid)
+}
+
+func TestMapKeyParserError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_GRID_A": "val"})()
+
+	var c GridConfig
+	e := enviper.New(viper.New()).WithMapKeyParser(reflect.TypeOf(Point{}), parsePoint)
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `can't parse "a" as point`)
+	}
+}
+
+type MapKeysConfig struct {
+	Servers map[string]*Server
+	Limits  map[string]int
+}
+
+func TestMapKeysEnv(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+servers:
+  db:
+    host: db.local
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_SERVERS_KEYS":       "web, api db",
+		"APP_SERVERS_WEB_HOST":   "web.local",
+		"APP_SERVERS_DB_TLS_CA":  "ca",
+		"APP_SERVERS_ADMIN_HOST": "admin.local",
+		"APP_LIMITS_READ":        "10",
+	})()
+
+	e := enviper.New(viper.New()).WithMapKeysEnv()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c MapKeysConfig
+	if !assert.Nil(t, e.Unmarshal(&c)) {
+		return
+	}
+	// admin is not listed, so it's not bound, while api is created without any env variable of its fields
+	if assert.Len(t, c.Servers, 3) {
+		assert.Equal(t, &Server{Host: "web.local"}, c.Servers["web"])
+		assert.Equal(t, &Server{}, c.Servers["api"])
+		assert.Equal(t, "db.local", c.Servers["db"].Host)
+		assert.Equal(t, []string{"ca"}, c.Servers["db"].TLS.CA)
+	}
+	// maps without the keys env variable are still scanned
+	assert.Equal(t, map[string]int{"read": 10}, c.Limits)
+}
+
+func TestMapKeysEnvDisabled(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_SERVERS_KEYS":     "api",
+		"APP_SERVERS_WEB_HOST": "web.local",
+	})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	var c MapKeysConfig
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, map[string]*Server{"web": {Host: "web.local"}}, c.Servers)
+}
+
+func TestNestedMapsWithNamedKeys(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+urls:
+  web:
+    dev: http://web.dev
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_URLS_API_PROD": "http://api",
+		"APP_URLS_API_DEV":  "http://api.dev",
+		"APP_URLS_WEB_PROD": "http://web",
+	})()
+
+	var c struct {
+		URLs map[string]map[Environment]string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, map[string]map[Environment]string{
+			"api": {"prod": "http://api", "dev": "http://api.dev"},
+			"web": {"prod": "http://web", "dev": "http://web.dev"},
+		}, c.URLs)
+	}
+}
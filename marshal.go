@@ -0,0 +1,237 @@
+package enviper
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalEnv returns env variables that make Unmarshal produce the same value as rawVal.
+// Elements of slices are marshaled by their indexes (e.g. `PREFIX_TAGS_0`),
+// zero values outside of slices are omitted as they are the defaults anyway.
+func (e *Enviper) MarshalEnv(rawVal interface{}) (map[string]string, error) {
+	env := map[string]string{}
+	var errs []string
+	e.walkElements(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		if !f.value.IsValid() || f.value.Kind() == reflect.Interface && f.value.IsNil() {
+			return
+		}
+		if kind := f.value.Kind(); (kind == reflect.Map || kind == reflect.Slice) && !e.isLeaf(f.value.Type()) {
+			return
+		}
+		if !f.indexed && f.value.IsZero() {
+			return
+		}
+		val, err := marshalValue(f.value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", strings.Join(f.path, "."), err))
+			return
+		}
+		env[e.fieldEnvName(f)] = val
+	}, allElements)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("can't marshal env: %s", strings.Join(errs, "; "))
+	}
+	return env, nil
+}
+
+// allElements returns indexes of all elements of the slice
+func allElements(f field) []int {
+	indexes := make([]int, f.value.Len())
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+func marshalValue(v reflect.Value) (string, error) {
+	if v.CanInterface() {
+		switch i := v.Interface().(type) {
+		case url.Values:
+			return i.Encode(), nil
+		case time.Location:
+			return (&i).String(), nil
+		case url.URL:
+			return (&i).String(), nil
+		case encoding.TextMarshaler:
+			b, err := i.MarshalText()
+			return string(b), err
+		case json.Marshaler:
+			b, err := i.MarshalJSON()
+			var s string
+			if err == nil && json.Unmarshal(b, &s) == nil {
+				return s, nil
+			}
+			return string(b), err
+		case fmt.Stringer:
+			return i.String(), nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// VerifyRoundTrip marshals rawVal to env variables, unmarshals them to a fresh value of the same type
+// and returns an error describing every difference between the two values.
+// It's intended for tests, that make sure encoding and decoding of the config agree.
+// Env variables bound to the config are replaced while verifying and restored afterwards,
+// so it's not safe to use concurrently with anything that reads env.
+func (e *Enviper) VerifyRoundTrip(rawVal interface{}) error {
+	env, err := e.MarshalEnv(rawVal)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(rawVal)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Ptr || !rv.IsValid() {
+		return fmt.Errorf("can't verify round trip of nil value")
+	}
+	fresh := reflect.New(rv.Type())
+
+	names := map[string]bool{}
+	for name := range env {
+		names[name] = true
+	}
+	collect := func(f field) {
+		names[e.fieldEnvName(f)] = true
+	}
+	e.walk(field{value: rv}, collect)
+	e.walk(field{value: fresh}, collect)
+	defer restoreEnv(names)()
+	for name := range names {
+		os.Unsetenv(name)
+	}
+	for name, val := range env {
+		if err := os.Setenv(name, val); err != nil {
+			return err
+		}
+	}
+
+	if err := e.Unmarshal(fresh.Interface()); err != nil {
+		return fmt.Errorf("can't unmarshal marshaled env: %s", err)
+	}
+	if diffs := diffValues("", rv, fresh.Elem()); len(diffs) > 0 {
+		return fmt.Errorf("round trip mismatch: %s", strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+// restoreEnv returns the function that restores current values of env variables
+func restoreEnv(names map[string]bool) func() {
+	values := map[string]*string{}
+	for name := range names {
+		if val, ok := os.LookupEnv(name); ok {
+			values[name] = &val
+		} else {
+			values[name] = nil
+		}
+	}
+	return func() {
+		for name, val := range values {
+			if val == nil {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, *val)
+			}
+		}
+	}
+}
+
+// diffValues returns paths of differences between values along with both values
+func diffValues(path string, a, b reflect.Value) []string {
+	if a.Type() != b.Type() {
+		return []string{fmt.Sprintf("%s: type %s != %s", path, a.Type(), b.Type())}
+	}
+	if reflect.DeepEqual(valueInterface(a), valueInterface(b)) {
+		return nil
+	}
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	var diffs []string
+	switch a.Kind() {
+	case reflect.Ptr:
+		if !a.IsNil() && !b.IsNil() {
+			return diffValues(path, a.Elem(), b.Elem())
+		}
+	case reflect.Struct:
+		if a.Type() == timeType {
+			break
+		}
+		for i := 0; i < a.NumField(); i++ {
+			diffs = append(diffs, diffValues(join(a.Type().Field(i).Name), a.Field(i), b.Field(i))...)
+		}
+		return diffs
+	case reflect.Slice:
+		if a.Len() == b.Len() {
+			for i := 0; i < a.Len(); i++ {
+				diffs = append(diffs, diffValues(join(strconv.Itoa(i)), a.Index(i), b.Index(i))...)
+			}
+			return diffs
+		}
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			av, bv := a.MapIndex(keys[name]), b.MapIndex(keys[name])
+			if !av.IsValid() || !bv.IsValid() {
+				diffs = append(diffs, fmt.Sprintf("%s: %s != %s", join(name), formatValue(av), formatValue(bv)))
+				continue
+			}
+			diffs = append(diffs, diffValues(join(name), av, bv)...)
+		}
+		return diffs
+	}
+	return []string{fmt.Sprintf("%s: %s != %s", path, formatValue(a), formatValue(b))}
+}
+
+func valueInterface(v reflect.Value) interface{} {
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	// unexported fields are compared by their string representation
+	return fmt.Sprintf("%v", v)
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<missing>"
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return fmt.Sprintf("%#v", valueInterface(v))
+}
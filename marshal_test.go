@@ -0,0 +1,130 @@
+package enviper_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type RoundTripConfig struct {
+	Name     string
+	Port     int
+	Ratio    float64
+	Debug    bool
+	Timeout  time.Duration
+	Started  time.Time
+	Tags     []string
+	Servers  []Server
+	Labels   map[string]string
+	Limits   map[string]struct{ Max int }
+	Optional *PtrTest
+}
+
+func roundTripConfig() RoundTripConfig {
+	c := RoundTripConfig{
+		Name:    "app",
+		Port:    8080,
+		Ratio:   0.25,
+		Debug:   true,
+		Timeout: 90 * time.Second,
+		Started: time.Date(2024, 1, 2, 13, 4, 5, 0, time.UTC),
+		Tags:    []string{"a", "b,c"},
+		Servers: []Server{{Host: "first"}, {Host: "second"}},
+		Labels:  map[string]string{"env": "prod", "team": "core"},
+		Limits:  map[string]struct{ Max int }{"cpu": {Max: 4}},
+		Optional: &PtrTest{
+			Value: "set",
+		},
+	}
+	c.Servers[1].TLS.Cert = "second.crt"
+	c.Servers[1].TLS.CA = []string{"ca0", "ca1"}
+	return c
+}
+
+func TestMarshalEnv(t *testing.T) {
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	env, err := e.MarshalEnv(roundTripConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"APP_NAME":                       "app",
+		"APP_PORT":                       "8080",
+		"APP_RATIO":                      "0.25",
+		"APP_DEBUG":                      "true",
+		"APP_TIMEOUT":                    "1m30s",
+		"APP_STARTED":                    "2024-01-02T13:04:05Z",
+		"APP_TAGS_0":                     "a",
+		"APP_TAGS_1":                     "b,c",
+		"APP_SERVERS_0_HOST":             "first",
+		"APP_SERVERS_0_TLS_CERT":         "",
+		"APP_SERVERS_0_TLS_KEY":          "",
+		"APP_SERVERS_0_TLS_OPTIONS_MODE": "",
+		"APP_SERVERS_1_HOST":             "second",
+		"APP_SERVERS_1_TLS_CERT":         "second.crt",
+		"APP_SERVERS_1_TLS_KEY":          "",
+		"APP_SERVERS_1_TLS_CA_0":         "ca0",
+		"APP_SERVERS_1_TLS_CA_1":         "ca1",
+		"APP_SERVERS_1_TLS_OPTIONS_MODE": "",
+		"APP_LABELS_ENV":                 "prod",
+		"APP_LABELS_TEAM":                "core",
+		"APP_LIMITS_CPU_MAX":             "4",
+		"APP_OPTIONAL_VALUE":             "set",
+	}, env)
+}
+
+func TestMarshalEnvUnsupportedType(t *testing.T) {
+	var c struct {
+		Callback func()
+	}
+	c.Callback = func() {}
+	_, err := enviper.New(viper.New()).MarshalEnv(c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Callback: unsupported type func()")
+	}
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+labels:
+  env: dev
+  team: none
+limits:
+  cpu:
+    max: 1
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_NAME": "untouched"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	c := roundTripConfig()
+	assert.Nil(t, e.VerifyRoundTrip(&c))
+	assert.Equal(t, "untouched", os.Getenv("APP_NAME"))
+	_, ok := os.LookupEnv("APP_PORT")
+	assert.False(t, ok)
+}
+
+func TestVerifyRoundTripMismatch(t *testing.T) {
+	var c struct {
+		Name  string
+		Count *int
+	}
+	c.Name = "app"
+	c.Count = new(int)
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	err := e.VerifyRoundTrip(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "round trip mismatch: Count: 0 != (*int)(nil)", err.Error())
+	}
+}
@@ -0,0 +1,109 @@
+package enviper
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Metadata is mapstructure's metadata of decoding extended with keys set from env variables
+type Metadata struct {
+	mapstructure.Metadata
+	// EnvKeys are sorted config keys of fields set from env variables, e.g. `db.host` or `servers.0.port`
+	EnvKeys []string
+	// Shadowed are fields set by several env variables at once, e.g. by the one scoped by profile and the unscoped one,
+	// sorted by their config keys
+	Shadowed []ShadowedEnv
+}
+
+// ShadowedEnv describes the env variable that won over other env variables setting the same field
+type ShadowedEnv struct {
+	// Key is the config key of the field
+	Key string
+	// Env is the name of env variable the field is set by
+	Env string
+	// Shadowed are the names of other env variables that are set, but ignored, in order of priority
+	Shadowed []string
+}
+
+// WithMetadata makes every Unmarshal fill md with metadata of decoding of the config
+func (e *Enviper) WithMetadata(md *Metadata) *Enviper {
+	e.metadata = md
+	return e
+}
+
+// metadataOption returns the decoder config option collecting mapstructure's metadata to e.metadata
+func (e *Enviper) metadataOption() func(*mapstructure.DecoderConfig) {
+	e.metadata.Metadata = mapstructure.Metadata{}
+	return func(c *mapstructure.DecoderConfig) {
+		c.Metadata = &e.metadata.Metadata
+	}
+}
+
+// envKeys returns sorted keys of fields of rawVal that are set by env variables
+func (e *Enviper) envKeys(rawVal interface{}) []string {
+	keys := []string{}
+	e.walk(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		if f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+			return
+		}
+		set := false
+		if val, ok := e.lookupEnv(e.fieldEnvName(f)); ok && val != "" {
+			set = true
+		}
+		if f.value.IsValid() && e.isNumbered(f.value.Type()) && len(e.envIndexes(f.env)) > 0 {
+			set = true
+		}
+		if set {
+			keys = append(keys, strings.ToLower(strings.Join(f.path, ".")))
+		}
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// shadowedEnvs returns fields of rawVal that are set by several env variables
+func (e *Enviper) shadowedEnvs(rawVal interface{}) []ShadowedEnv {
+	shadowed := []ShadowedEnv{}
+	profile := e.profile()
+	e.walk(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		if f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+			return
+		}
+		if set := e.setCandidates(f, profile); len(set) > 1 {
+			shadowed = append(shadowed, ShadowedEnv{Key: strings.ToLower(strings.Join(f.path, ".")), Env: set[0], Shadowed: set[1:]})
+		}
+	})
+	sort.Slice(shadowed, func(i, j int) bool {
+		return shadowed[i].Key < shadowed[j].Key
+	})
+	return shadowed
+}
+
+// setCandidates returns the names of env variables that are set and not empty and could set the field in order of priority
+func (e *Enviper) setCandidates(f field, profile string) []string {
+	var set []string
+	for _, env := range e.envCandidates(f, profile) {
+		if val, ok := e.lookupEnv(env); ok && val != "" {
+			set = append(set, env)
+		}
+	}
+	return set
+}
+
+// envCandidates returns the names of env variables that could set the field in order of priority
+func (e *Enviper) envCandidates(f field, profile string) []string {
+	var names []string
+	if profile != "" && e.profileScoped(f) {
+		names = append(names, e.profileEnvName(profile, f.env))
+	}
+	if env, ok := e.boundEnvs[strings.ToLower(strings.Join(f.path, "."))]; ok {
+		return append(names, env)
+	}
+	if len(f.envNames) > 0 {
+		return append(names, f.envNames...)
+	}
+	return append(names, e.envName(f.env))
+}
@@ -0,0 +1,86 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type MetadataConfig struct {
+	Host    string
+	Port    int
+	DB      struct{ Name string }
+	Servers []struct{ Host string }
+	Limits  map[string]int
+}
+
+func TestWithMetadata(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+host: file
+port: 80
+unknown: 1
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_PORT":           "8080",
+		"APP_DB_NAME":        "db",
+		"APP_SERVERS_0_HOST": "a",
+		"APP_LIMITS_READ":    "10",
+	})()
+
+	var md enviper.Metadata
+	e := enviper.New(viper.New()).WithMetadata(&md)
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c MetadataConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, []string{"db.name", "limits.read", "port", "servers.0.host"}, md.EnvKeys)
+		assert.Contains(t, md.Keys, "Host")
+		assert.Contains(t, md.Keys, "Port")
+		assert.Equal(t, []string{"unknown"}, md.Unused)
+	}
+
+	// metadata is refreshed by every call
+	defer setenv(t, map[string]string{"APP_HOST": "env"})()
+	if assert.Nil(t, e.Unmarshal(&c, enviper.CallEnvPrefix("OTHER"))) {
+		assert.Equal(t, []string{}, md.EnvKeys)
+		assert.Equal(t, []string{"unknown"}, md.Unused)
+	}
+}
+
+type ShadowedConfig struct {
+	Host string
+	Port int
+	URL  string `env:"DATABASE_URL,DB_URL,noprefix"`
+	Name string
+}
+
+func TestMetadataShadowed(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_PROFILE":   "prod",
+		"APP_PROD_HOST": "prod.internal",
+		"APP_HOST":      "localhost",
+		"APP_PROD_PORT": "443",
+		"DATABASE_URL":  "postgres://a",
+		"DB_URL":        "postgres://b",
+		"APP_NAME":      "svc",
+	})()
+
+	var md enviper.Metadata
+	e := enviper.New(viper.New()).WithMetadata(&md).WithProfileEnv("APP_PROFILE")
+	e.SetEnvPrefix("APP")
+
+	var c ShadowedConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "prod.internal", c.Host)
+		assert.Equal(t, "postgres://a", c.URL)
+		assert.Equal(t, []enviper.ShadowedEnv{
+			{Key: "host", Env: "APP_PROD_HOST", Shadowed: []string{"APP_HOST"}},
+			{Key: "url", Env: "DATABASE_URL", Shadowed: []string{"DB_URL"}},
+		}, md.Shadowed)
+	}
+}
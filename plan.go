@@ -0,0 +1,71 @@
+package enviper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldBinding describes how the field of the config is bound, it's an entry of BindingPlan
+type FieldBinding struct {
+	// Key is the lowercased config key of the field, e.g. `db.host`
+	Key string `json:"key"`
+	// Env is the name of env variable bound to the field, for maps it's the prefix of env variables of entries
+	Env string `json:"env"`
+	// Type is the Go type of the field, pointers are dereferenced
+	Type string `json:"type"`
+	// Slice is true for slices and arrays, their elements are bound by indexes too
+	Slice bool `json:"slice,omitempty"`
+	// Map is true for maps, their entries are bound by keys
+	Map bool `json:"map,omitempty"`
+	// Options are the options of the tag of the field, e.g. `required`
+	Options []string `json:"options,omitempty"`
+	// Default is the value rawVal has, formatted like env variables are, if it's set, otherwise the value of `default` tag
+	Default interface{} `json:"default,omitempty"`
+}
+
+// BindingPlan returns JSON array describing how every field of rawVal is bound, sorted by keys, e.g. for tooling and debugging.
+// Values rawVal has, or values of `default` tags of fields it has zero values of, are reported as defaults, entries of its maps are listed as fields, elements of slices are not.
+// Nothing is read or bound, so it could be called before Unmarshal.
+func (e *Enviper) BindingPlan(rawVal interface{}) ([]byte, error) {
+	t := reflect.TypeOf(rawVal)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't build binding plan of %T, it's not a struct", rawVal)
+	}
+
+	plan := []FieldBinding{}
+	e.walkElements(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		if len(f.path) == 0 || !f.value.IsValid() {
+			return
+		}
+		kind := f.value.Kind()
+		leaf := e.isLeaf(f.value.Type())
+		b := FieldBinding{
+			Key:     strings.ToLower(strings.Join(f.path, ".")),
+			Env:     e.fieldEnvName(f),
+			Type:    f.value.Type().String(),
+			Slice:   (kind == reflect.Slice || kind == reflect.Array) && !leaf,
+			Map:     kind == reflect.Map && !leaf,
+			Options: f.opts,
+		}
+		if e.isValueSet(f.value) {
+			b.Default = valueInterface(f.value)
+			if val, err := marshalValue(f.value); err == nil {
+				b.Default = val
+			}
+		} else if f.defaultValue != "" {
+			b.Default = f.defaultValue
+		}
+		plan = append(plan, b)
+	}, func(field) []int { return nil })
+
+	sort.SliceStable(plan, func(i, j int) bool {
+		return plan[i].Key < plan[j].Key
+	})
+	return json.MarshalIndent(plan, "", "  ")
+}
@@ -0,0 +1,51 @@
+package enviper_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type PlanConfig struct {
+	Host    string        `mapstructure:"host,required"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Tags    []string
+	Port    int    `default:"8080"`
+	Level   string `default:"info"`
+	Limits  map[string]int
+	DB      *struct {
+		User string
+	}
+}
+
+func TestBindingPlan(t *testing.T) {
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	c := PlanConfig{Level: "debug", Timeout: 5 * time.Second, Limits: map[string]int{"read": 10}}
+	raw, err := e.BindingPlan(&c)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var plan []map[string]interface{}
+	if !assert.Nil(t, json.Unmarshal(raw, &plan)) {
+		return
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"key": "db.user", "env": "APP_DB_USER", "type": "string"},
+		{"key": "host", "env": "APP_HOST", "type": "string", "options": []interface{}{"required"}},
+		{"key": "level", "env": "APP_LEVEL", "type": "string", "default": "debug"},
+		{"key": "limits", "env": "APP_LIMITS", "type": "map[string]int", "map": true, "default": map[string]interface{}{"read": float64(10)}},
+		{"key": "limits.read", "env": "APP_LIMITS_READ", "type": "int", "default": "10"},
+		{"key": "port", "env": "APP_PORT", "type": "int", "default": "8080"},
+		{"key": "tags", "env": "APP_TAGS", "type": "[]string", "slice": true},
+		{"key": "timeout", "env": "APP_TIMEOUT", "type": "time.Duration", "default": "5s"},
+	}, plan)
+
+	_, err = e.BindingPlan(42)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,137 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RegisterPostDecode registers the func transforming the value of the field by its config key (e.g. `db.url`)
+// after the config is unmarshaled, e.g. to normalize URLs or lowercase emails:
+//
+//	e.RegisterPostDecode("admin.email", func(current interface{}) (interface{}, error) {
+//		return strings.ToLower(current.(string)), nil
+//	})
+//
+// Elements of slices and maps are addressed by indexes and keys, e.g. `servers.0.host`.
+// Funcs are not called for fields inside of nil pointers and missing elements.
+func (e *Enviper) RegisterPostDecode(path string, fn func(current interface{}) (interface{}, error)) *Enviper {
+	if e.postDecoders == nil {
+		e.postDecoders = map[string]func(interface{}) (interface{}, error){}
+	}
+	e.postDecoders[strings.ToLower(path)] = fn
+	return e
+}
+
+// applyPostDecoders transforms fields with registered funcs in order of their paths
+func (e *Enviper) applyPostDecoders(rawVal interface{}) error {
+	paths := make([]string, 0, len(e.postDecoders))
+	for path := range e.postDecoders {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := e.postDecode(reflect.ValueOf(rawVal), strings.Split(path, "."), e.postDecoders[path]); err != nil {
+			return fmt.Errorf("can't post decode %s: %s", path, err)
+		}
+	}
+	return nil
+}
+
+// postDecode transforms the value by the path with fn
+func (e *Enviper) postDecode(v reflect.Value, path []string, fn func(interface{}) (interface{}, error)) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if len(path) == 0 {
+		if !v.CanSet() {
+			return fmt.Errorf("field can't be set")
+		}
+		out, err := fn(valueInterface(v))
+		if err != nil {
+			return err
+		}
+		ov := reflect.ValueOf(out)
+		switch {
+		case !ov.IsValid():
+			v.Set(reflect.Zero(v.Type()))
+		case ov.Type().AssignableTo(v.Type()):
+			v.Set(ov)
+		case ov.Kind() == v.Kind() && ov.Type().ConvertibleTo(v.Type()):
+			// named types, e.g. string returned for `type Level string`
+			v.Set(ov.Convert(v.Type()))
+		default:
+			return fmt.Errorf("can't set %T to the field of type %s", out, v.Type())
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if fv, ok := e.fieldByKey(v, path[0]); ok {
+			return e.postDecode(fv, path[1:], fn)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		key := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		elem := v.MapIndex(key)
+		if !elem.IsValid() {
+			return nil
+		}
+		// elements of maps are not addressable, so a copy is transformed and put back
+		cp := reflect.New(elem.Type()).Elem()
+		cp.Set(elem)
+		if err := e.postDecode(cp, path[1:], fn); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, cp)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(path[0])
+		if err != nil {
+			break
+		}
+		if i < 0 || i >= v.Len() {
+			return nil
+		}
+		return e.postDecode(v.Index(i), path[1:], fn)
+	}
+	return fmt.Errorf("%s has no field %s", v.Type(), path[0])
+}
+
+// fieldByKey returns the field of the struct by its config key case insensitively, squashed fields are searched too
+func (e *Enviper) fieldByKey(v reflect.Value, key string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" {
+			continue
+		}
+		if e.isSquashed(sf, name, opts) {
+			fv := v.Field(i)
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if found, ok := e.fieldByKey(fv, key); ok {
+					return found, true
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if strings.EqualFold(name, key) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
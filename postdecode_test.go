@@ -0,0 +1,96 @@
+package enviper_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type PostDecodeConfig struct {
+	Email string
+	Admin struct {
+		URL string `mapstructure:"url"`
+	}
+	Servers []struct {
+		Host string
+	}
+	Hosts map[string]string
+	Port  *int
+}
+
+func normalizeURL(current interface{}) (interface{}, error) {
+	u, err := url.Parse(current.(string))
+	if err != nil {
+		return nil, err
+	}
+	u.Host = strings.ToLower(u.Host)
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+func lower(current interface{}) (interface{}, error) {
+	return strings.ToLower(current.(string)), nil
+}
+
+func TestPostDecode(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_EMAIL":          "Admin@Example.COM",
+		"APP_ADMIN_URL":      "https://Example.COM/admin/",
+		"APP_SERVERS_0_HOST": "A.local",
+		"APP_HOSTS_MAIN":     "B.local",
+	})()
+
+	var c PostDecodeConfig
+	e := enviper.New(viper.New()).
+		RegisterPostDecode("email", lower).
+		RegisterPostDecode("Admin.URL", normalizeURL).
+		RegisterPostDecode("servers.0.host", lower).
+		RegisterPostDecode("servers.1.host", lower).
+		RegisterPostDecode("hosts.main", lower).
+		RegisterPostDecode("port", func(interface{}) (interface{}, error) {
+			return nil, errors.New("not called for nil pointers")
+		})
+	e.SetEnvPrefix("APP")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "admin@example.com", c.Email)
+		assert.Equal(t, "https://example.com/admin", c.Admin.URL)
+		if assert.Len(t, c.Servers, 1) {
+			assert.Equal(t, "a.local", c.Servers[0].Host)
+		}
+		assert.Equal(t, map[string]string{"main": "b.local"}, c.Hosts)
+	}
+}
+
+func TestPostDecodeError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_ADMIN_URL": "http://[::1"})()
+
+	var c PostDecodeConfig
+	e := enviper.New(viper.New()).RegisterPostDecode("admin.url", normalizeURL)
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't post decode admin.url: ")
+	}
+}
+
+func TestPostDecodeInvalid(t *testing.T) {
+	for path, message := range map[string]string{
+		"admin.missing": "can't post decode admin.missing: struct { URL string \"mapstructure:\\\"url\\\"\" } has no field missing",
+		"email":         "can't post decode email: can't set int to the field of type string",
+	} {
+		var c PostDecodeConfig
+		e := enviper.New(viper.New()).RegisterPostDecode(path, func(interface{}) (interface{}, error) {
+			return 1, nil
+		})
+		err := e.Unmarshal(&c)
+		if assert.NotNil(t, err, path) {
+			assert.Equal(t, message, err.Error(), path)
+		}
+	}
+}
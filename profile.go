@@ -0,0 +1,47 @@
+package enviper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// WithProfileEnv sets the env variable with the name of active profile, e.g. `APP_PROFILE=prod`.
+// Env variables scoped by the profile, like `APP_PROD_DB_HOST`, take precedence over unscoped ones like `APP_DB_HOST`,
+// that are still used for fields without scoped env variables. The name of env variable is used as is, without env prefix.
+// Keys of maps and indexes of slices are found by unscoped env variables only.
+func (e *Enviper) WithProfileEnv(name string) *Enviper {
+	e.profileEnv = name
+	return e
+}
+
+// profile returns the name of active profile, if any
+func (e *Enviper) profile() string {
+	if e.profileEnv == "" {
+		return ""
+	}
+	val, _ := e.lookupEnv(e.profileEnv)
+	return strings.TrimSpace(val)
+}
+
+// profileEnvName returns the name of env variable of the env path scoped by the profile
+func (e *Enviper) profileEnvName(profile string, path []string) string {
+	if suffix := e.prefixSuffix(); suffix != "" {
+		return e.prefixedEnvName(profile, path) + suffix
+	}
+	if e.envPrefix != "" {
+		profile = e.envPrefix + "_" + profile
+	}
+	return e.prefixedEnvName(profile, path)
+}
+
+// profileScoped reports whether the field could be set by env variable scoped by the profile
+func (e *Enviper) profileScoped(f field) bool {
+	return len(f.envNames) == 0 && !e.isSet(f) && (f.value.Kind() != reflect.Map || e.isLeaf(f.value.Type()))
+}
+
+// overrideFromProfile collects the value of env variable of the field scoped by the profile
+func (e *Enviper) overrideFromProfile(f field, profile string) {
+	if val, ok := e.lookupEnv(e.profileEnvName(profile, f.env)); ok && val != "" {
+		e.overrides[strings.Join(f.path, ".")] = val
+	}
+}
@@ -0,0 +1,67 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type ProfileConfig struct {
+	Host string
+	Port int
+	Tags []string
+	DB   struct {
+		User string
+	}
+}
+
+func TestProfileEnv(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+host: file.local
+port: 80
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_PROFILE":       "prod",
+		"APP_HOST":          "base.local",
+		"APP_PROD_HOST":     "prod.local",
+		"APP_DB_USER":       "base",
+		"APP_PROD_TAGS":     "a,b",
+		"APP_TAGS_1":        "z",
+		"APP_STAGE_DB_USER": "stage",
+	})()
+
+	e := enviper.New(viper.New()).WithProfileEnv("APP_PROFILE")
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c ProfileConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "prod.local", c.Host)
+		assert.Equal(t, 80, c.Port)
+		assert.Equal(t, []string{"a", "z"}, c.Tags)
+		assert.Equal(t, "base", c.DB.User)
+	}
+
+	problems := e.LintEnv(&c)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "APP_STAGE_DB_USER", problems[0].Env)
+	}
+}
+
+func TestProfileEnvNotSet(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_HOST":      "base.local",
+		"APP_PROD_HOST": "prod.local",
+	})()
+
+	e := enviper.New(viper.New()).WithProfileEnv("APP_PROFILE")
+	e.SetEnvPrefix("APP")
+
+	var c ProfileConfig
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "base.local", c.Host)
+}
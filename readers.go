@@ -0,0 +1,59 @@
+package enviper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldReader computes the value of a field from env variables, that are looked up with the lookup func.
+// It returns nil when the field is not set.
+type FieldReader func(lookup func(string) (string, bool)) (interface{}, error)
+
+// RegisterFieldReader registers the reader computing the value of the field by its config key (e.g. `db.dsn`),
+// bypassing default derivation of env variable name. It's useful for one-off legacy env variables:
+//
+//	e.RegisterFieldReader("db.dsn", func(lookup func(string) (string, bool)) (interface{}, error) {
+//		user, _ := lookup("PGUSER")
+//		host, ok := lookup("PGHOST")
+//		if !ok {
+//			return nil, nil
+//		}
+//		return "postgres://" + user + "@" + host, nil
+//	})
+//
+// The value takes precedence over both config file and env variables, just like the value set with viper.Set.
+func (e *Enviper) RegisterFieldReader(path string, reader FieldReader) *Enviper {
+	if e.fieldReaders == nil {
+		e.fieldReaders = map[string]FieldReader{}
+	}
+	e.fieldReaders[path] = reader
+	return e
+}
+
+// readFields collects values of registered field readers to overrides
+func (e *Enviper) readFields() error {
+	paths := make([]string, 0, len(e.fieldReaders))
+	for path := range e.fieldReaders {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var errs []string
+	for _, path := range paths {
+		if e.failed(errs) {
+			break
+		}
+		val, err := e.fieldReaders[path](e.lookupEnv)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", path, err))
+			continue
+		}
+		if val != nil {
+			e.overrides[path] = val
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("can't read fields: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
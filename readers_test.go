@@ -0,0 +1,80 @@
+package enviper_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func dsnReader(lookup func(string) (string, bool)) (interface{}, error) {
+	host, ok := lookup("PGHOST")
+	if !ok {
+		return nil, nil
+	}
+	user, _ := lookup("PGUSER")
+	port, ok := lookup("PGPORT")
+	if !ok {
+		port = "5432"
+	}
+	return "postgres://" + user + "@" + host + ":" + port, nil
+}
+
+type ReaderConfig struct {
+	Name string
+	DB   struct {
+		DSN  string
+		Pool int
+	}
+}
+
+func TestRegisterFieldReader(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+db:
+  dsn: postgres://file@localhost:5432
+  pool: 5
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"PGHOST":      "db.internal",
+		"PGUSER":      "admin",
+		"APP_DB_DSN":  "postgres://env@localhost:5432",
+		"APP_DB_POOL": "10",
+	})()
+
+	var c ReaderConfig
+	e := enviper.New(viper.New()).RegisterFieldReader("db.dsn", dsnReader)
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "postgres://admin@db.internal:5432", c.DB.DSN)
+	assert.Equal(t, 10, c.DB.Pool)
+}
+
+func TestRegisterFieldReaderNotSet(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_DB_DSN": "postgres://env@localhost:5432"})()
+
+	var c ReaderConfig
+	e := enviper.New(viper.New()).RegisterFieldReader("db.dsn", dsnReader)
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "postgres://env@localhost:5432", c.DB.DSN)
+}
+
+func TestRegisterFieldReaderError(t *testing.T) {
+	var c ReaderConfig
+	e := enviper.New(viper.New()).
+		RegisterFieldReader("db.pool", func(func(string) (string, bool)) (interface{}, error) {
+			return nil, errors.New("pool size is unknown")
+		})
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "can't read fields: db.pool: pool size is unknown", err.Error())
+	}
+}
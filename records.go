@@ -0,0 +1,83 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	recordSeparator = "\x1e"
+	unitSeparator   = "\x1f"
+)
+
+// WithRecordSeparators makes slices of structs accept values separated by ASCII record and unit separators,
+// so elements are fed via env without JSON and its quoting: records (`\x1e`) are elements
+// and units (`\x1f`) are values of fields in order of their declaration,
+// e.g. `MYAPP_PARAMS=$'a\x1f1\x1eb\x1f2'` is `[]Param{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}`.
+// Empty units leave fields empty, missing trailing units are not set.
+func (e *Enviper) WithRecordSeparators() *Enviper {
+	e.recordSeparators = true
+	return e
+}
+
+// recordsHook parses values with record and unit separators of slices of structs
+func (e *Enviper) recordsHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Slice {
+		return data, nil
+	}
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct || e.isLeaf(et) {
+		return data, nil
+	}
+	raw := reflect.ValueOf(data).String()
+	if !strings.Contains(raw, recordSeparator) && !strings.Contains(raw, unitSeparator) {
+		return data, nil
+	}
+
+	keys := e.fieldKeys(et)
+	records := strings.Split(raw, recordSeparator)
+	list := make([]interface{}, 0, len(records))
+	for i, record := range records {
+		units := strings.Split(record, unitSeparator)
+		if len(units) > len(keys) {
+			return nil, fmt.Errorf("record %d has %d fields, but %s has %d", i, len(units), et, len(keys))
+		}
+		elem := make(map[string]interface{}, len(units))
+		for j, unit := range units {
+			elem[keys[j]] = unit
+		}
+		list = append(list, elem)
+	}
+	return list, nil
+}
+
+// fieldKeys returns keys of fields of the struct in order of their declaration, squashed fields are flattened
+func (e *Enviper) fieldKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" || sf.PkgPath != "" && !e.setterBinding {
+			continue
+		}
+		if e.isSquashed(sf, name, opts) {
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				keys = append(keys, e.fieldKeys(ft)...)
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
@@ -0,0 +1,66 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type RecordParam struct {
+	Name  string
+	Value string
+	Port  int `mapstructure:"port"`
+}
+
+type RecordsConfig struct {
+	Params   []RecordParam
+	Pointers []*RecordParam
+	Tags     []string
+}
+
+func TestRecordSeparators(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_PARAMS":   "a\x1f1\x1f80\x1e\x1f2\x1eb\x1f\x1f443\x1ec",
+		"APP_POINTERS": "x\x1fsay \"hi\", then go",
+		"APP_TAGS":     "a,b",
+	})()
+
+	var c RecordsConfig
+	e := enviper.New(viper.New()).WithRecordSeparators()
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, []RecordParam{
+			{Name: "a", Value: "1", Port: 80},
+			{Value: "2"},
+			{Name: "b", Port: 443},
+			{Name: "c"},
+		}, c.Params)
+		assert.Equal(t, []*RecordParam{{Name: "x", Value: `say "hi", then go`}}, c.Pointers)
+		assert.Equal(t, []string{"a", "b"}, c.Tags)
+	}
+}
+
+func TestRecordSeparatorsWithJSON(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PARAMS": `[{"name":"a","port":1}]`})()
+
+	var c RecordsConfig
+	e := enviper.New(viper.New()).WithRecordSeparators()
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, []RecordParam{{Name: "a", Port: 1}}, c.Params)
+	}
+}
+
+func TestRecordSeparatorsTooManyFields(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PARAMS": "a\x1e1\x1f2\x1f3\x1f4"})()
+
+	var c RecordsConfig
+	e := enviper.New(viper.New()).WithRecordSeparators()
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "record 1 has 4 fields, but enviper_test.RecordParam has 3")
+	}
+}
@@ -0,0 +1,57 @@
+package enviper
+
+import (
+	"path/filepath"
+	"reflect"
+)
+
+// resolveRelPaths resolves relative paths of string fields with `relpath` tag option
+// (e.g. `mapstructure:"cert,relpath"`) against the directory of the config file.
+// Elements of slices and values of maps of such fields are resolved too.
+// Absolute paths, empty values and configs without config file are left as is.
+func (e *Enviper) resolveRelPaths(rawVal interface{}) {
+	file := e.ConfigFileUsed()
+	if file == "" {
+		return
+	}
+	e.resolveRelPath(reflect.ValueOf(rawVal), filepath.Dir(file), false)
+}
+
+func (e *Enviper) resolveRelPath(v reflect.Value, base string, rel bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if e.isLeaf(v.Type()) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			name, opts := parseTag(v.Type().Field(i).Tag.Get(e.TagName()))
+			if name != "-" {
+				e.resolveRelPath(v.Field(i), base, opts.has("relpath"))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			e.resolveRelPath(v.Index(i), base, rel)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// map values aren't addressable, so a copy is resolved and put back
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			e.resolveRelPath(value, base, rel)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.String:
+		if path := v.String(); rel && v.CanSet() && path != "" && !filepath.IsAbs(path) {
+			v.SetString(filepath.Join(base, path))
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package enviper_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type RelPathConfig struct {
+	Cert    string            `mapstructure:"cert,relpath"`
+	Key     *string           `mapstructure:"key,relpath"`
+	Abs     string            `mapstructure:"abs,relpath"`
+	Empty   string            `mapstructure:"empty,relpath"`
+	Plugins []string          `mapstructure:"plugins,relpath"`
+	Files   map[string]string `mapstructure:"files,relpath"`
+	Name    string            `mapstructure:"name"`
+}
+
+func TestRelPath(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+cert: certs/server.crt
+abs: /etc/server.key
+plugins: [a.so, /usr/lib/b.so]
+files:
+  main: main.txt
+name: plain
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_KEY": "../server.key"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c RelPathConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, filepath.Join(dir, "certs/server.crt"), c.Cert)
+		if assert.NotNil(t, c.Key) {
+			assert.Equal(t, filepath.Join(filepath.Dir(dir), "server.key"), *c.Key)
+		}
+		assert.Equal(t, "/etc/server.key", c.Abs)
+		assert.Equal(t, "", c.Empty)
+		assert.Equal(t, []string{filepath.Join(dir, "a.so"), "/usr/lib/b.so"}, c.Plugins)
+		assert.Equal(t, map[string]string{"main": filepath.Join(dir, "main.txt")}, c.Files)
+		assert.Equal(t, "plain", c.Name)
+	}
+}
+
+func TestRelPathInMapOfStructs(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+servers:
+  main:
+    cert: certs/main.crt
+    name: main
+  backup:
+    cert: /etc/backup.crt
+`)
+	defer cleanup()
+
+	type server struct {
+		Cert string `mapstructure:"cert,relpath"`
+		Name string `mapstructure:"name"`
+	}
+	var c struct {
+		Servers map[string]server `mapstructure:"servers"`
+	}
+	e := enviper.New(viper.New())
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, map[string]server{
+			"main":   {Cert: filepath.Join(dir, "certs/main.crt"), Name: "main"},
+			"backup": {Cert: "/etc/backup.crt"},
+		}, c.Servers)
+	}
+}
+
+func TestRelPathWithoutConfigFile(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_CERT": "certs/server.crt"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	var c RelPathConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "certs/server.crt", c.Cert)
+	}
+}
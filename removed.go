@@ -0,0 +1,35 @@
+package enviper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RegisterRemoved registers the env variable of the field that doesn't exist anymore.
+// When the env variable is set, Unmarshal returns an error with the message,
+// so long-lived deployments find out how to migrate instead of the value being silently ignored.
+//
+//	e.RegisterRemoved("MYAPP_DB_URL", "use MYAPP_DB_HOST and MYAPP_DB_PORT instead")
+func (e *Enviper) RegisterRemoved(envKey, message string) *Enviper {
+	if e.removed == nil {
+		e.removed = map[string]string{}
+	}
+	e.removed[envKey] = message
+	return e
+}
+
+// checkRemoved returns an error listing all set env variables of removed fields
+func (e *Enviper) checkRemoved() error {
+	var problems []string
+	for env, message := range e.removed {
+		if _, ok := e.lookupEnv(env); ok {
+			problems = append(problems, fmt.Sprintf("%s: %s", env, message))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("removed env variables are set: %s", strings.Join(problems, "; "))
+}
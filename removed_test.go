@@ -0,0 +1,50 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRemoved(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_DB_URL":  "postgres://localhost",
+		"APP_DB_USER": "admin",
+		"APP_DB_HOST": "localhost",
+	})()
+
+	var c struct {
+		DB struct {
+			Host string
+		}
+	}
+	e := enviper.New(viper.New()).
+		RegisterRemoved("APP_DB_URL", "use APP_DB_HOST instead").
+		RegisterRemoved("APP_DB_USER", "credentials are read from vault").
+		RegisterRemoved("APP_DB_NAME", "it's always app")
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "removed env variables are set: "+
+			"APP_DB_URL: use APP_DB_HOST instead; "+
+			"APP_DB_USER: credentials are read from vault", err.Error())
+	}
+}
+
+func TestRegisterRemovedNotSet(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_DB_HOST": "localhost"})()
+
+	var c struct {
+		DB struct {
+			Host string
+		}
+	}
+	e := enviper.New(viper.New()).RegisterRemoved("APP_DB_URL", "use APP_DB_HOST instead")
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "localhost", c.DB.Host)
+}
@@ -0,0 +1,137 @@
+package enviper
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/spf13/viper"
+)
+
+// Render unmarshals the config to rawVal and renders the result in the format (`yaml`, `json` or `toml`),
+// e.g. for `config show` commands. Unlike viper.AllSettings, the output reflects the typed config
+// after decode hooks, so durations, time zones and custom types are rendered as strings.
+// Keys are the lowercased names the fields have in the config file, nil values are omitted.
+func (e *Enviper) Render(rawVal interface{}, format string, opts ...viper.DecoderConfigOption) ([]byte, error) {
+	if err := e.Unmarshal(rawVal, opts...); err != nil {
+		return nil, err
+	}
+	doc, _ := e.renderable(reflect.ValueOf(rawVal)).(map[string]interface{})
+	if doc == nil {
+		return nil, fmt.Errorf("can't render %T, it's not a struct or map", rawVal)
+	}
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		return yaml.Marshal(doc)
+	case "json":
+		return json.MarshalIndent(doc, "", "  ")
+	case "toml":
+		tree, err := toml.TreeFromMap(doc)
+		if err != nil {
+			return nil, err
+		}
+		s, err := tree.ToTomlString()
+		return []byte(s), err
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// renderable converts the value to maps, slices and scalars that could be encoded in any format
+func (e *Enviper) renderable(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if v.CanInterface() {
+		switch v.Interface().(type) {
+		case encoding.TextMarshaler, fmt.Stringer:
+			s, _ := marshalValue(v)
+			return s
+		}
+	}
+	if e.isLeaf(v.Type()) {
+		s, err := marshalValue(v)
+		if err != nil {
+			return fmt.Sprintf("%v", valueInterface(v))
+		}
+		return s
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		doc := map[string]interface{}{}
+		e.renderStruct(v, doc)
+		return doc
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		doc := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			if val := e.renderable(iter.Value()); val != nil {
+				doc[fmt.Sprint(valueInterface(iter.Key()))] = val
+			}
+		}
+		return doc
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = e.renderable(v.Index(i))
+		}
+		return list
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return fmt.Sprintf("%v", valueInterface(v))
+	}
+}
+
+// renderStruct adds fields of the struct to doc by their keys, squashed fields are flattened
+func (e *Enviper) renderStruct(v reflect.Value, doc map[string]interface{}) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" || e.isSkipped(sf.Type) {
+			continue
+		}
+		fv := v.Field(i)
+		if e.isSquashed(sf, name, opts) {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				e.renderStruct(fv, doc)
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if val := e.renderable(fv); val != nil {
+			doc[strings.ToLower(name)] = val
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package enviper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type RenderConfig struct {
+	Name    string
+	Timeout time.Duration
+	DB      struct {
+		Host string `mapstructure:"hostname"`
+		Port int
+	}
+	Tags   []string
+	Labels map[string]string
+	Skip   *Server
+}
+
+func render(t *testing.T, format string) string {
+	dir, cleanup := writeConfig(t, `
+name: app
+timeout: 1m
+db:
+  hostname: localhost
+  port: 5432
+labels:
+  team: core
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_DB_PORT": "5433",
+		"APP_TAGS":    "a,b",
+	})()
+
+	var c RenderConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	out, err := e.Render(&c, format)
+	assert.Nil(t, err, format)
+	return string(out)
+}
+
+func TestRenderYAML(t *testing.T) {
+	assert.Equal(t, `db:
+  hostname: localhost
+  port: 5433
+labels:
+  team: core
+name: app
+tags:
+- a
+- b
+timeout: 1m0s
+`, render(t, "yaml"))
+}
+
+func TestRenderJSON(t *testing.T) {
+	assert.Equal(t, `{
+  "db": {
+    "hostname": "localhost",
+    "port": 5433
+  },
+  "labels": {
+    "team": "core"
+  },
+  "name": "app",
+  "tags": [
+    "a",
+    "b"
+  ],
+  "timeout": "1m0s"
+}`, render(t, "json"))
+}
+
+func TestRenderTOML(t *testing.T) {
+	assert.Equal(t, `name = "app"
+tags = ["a","b"]
+timeout = "1m0s"
+
+[db]
+  hostname = "localhost"
+  port = 5433
+
+[labels]
+  team = "core"
+`, render(t, "toml"))
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	var c RenderConfig
+	_, err := enviper.New(viper.New()).Render(&c, "ini")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `unsupported format "ini"`, err.Error())
+	}
+}
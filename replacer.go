@@ -0,0 +1,81 @@
+package enviper
+
+import (
+	"reflect"
+	"strings"
+)
+
+var stringsReplacerType = reflect.TypeOf((*strings.Replacer)(nil))
+
+// readViperReplacer returns the env key replacer set on viper directly and whether any is set.
+// Viper has no getter for it, so it's read by reflection without touching anything,
+// the replacer is nil when it's not a *strings.Replacer or its pairs can't be read,
+// then names of fields are still derived by viper itself, while the rest are derived with the default replacer.
+func (e *Enviper) readViperReplacer() (*strings.Replacer, bool) {
+	if e.Viper == nil {
+		return nil, false
+	}
+	field := reflect.ValueOf(e.Viper).Elem().FieldByName("envKeyReplacer")
+	if !field.IsValid() || field.Kind() != reflect.Interface || field.IsNil() {
+		return nil, false
+	}
+	source := field.Elem()
+	if source.Type() != stringsReplacerType || source.IsNil() {
+		return nil, true
+	}
+	if cached := e.viperReplacer.source; cached.IsValid() && cached.Pointer() == source.Pointer() {
+		return e.viperReplacer.replacer, true
+	}
+	switch source.Pointer() {
+	case reflect.ValueOf(envKeyReplacer).Pointer():
+		return envKeyReplacer, true
+	case reflect.ValueOf(e.userReplacer).Pointer():
+		return e.userReplacer, true
+	}
+	pairs, ok := replacerPairs(source.Elem())
+	if !ok {
+		return nil, true
+	}
+	e.viperReplacer.source, e.viperReplacer.replacer = source, strings.NewReplacer(pairs...)
+	return e.viperReplacer.replacer, true
+}
+
+// replacerPairs returns old and new strings the strings.Replacer was created with.
+// They are dropped once the replacer is built, so then they are recovered from replacers of single bytes,
+// that are built for pairs like `.` and `_` or `.` and `__`.
+func replacerPairs(r reflect.Value) ([]string, bool) {
+	if oldnew := r.FieldByName("oldnew"); oldnew.IsValid() && oldnew.Kind() == reflect.Slice && oldnew.Len() > 0 {
+		pairs := make([]string, oldnew.Len())
+		for i := range pairs {
+			pairs[i] = oldnew.Index(i).String()
+		}
+		return pairs, len(pairs)%2 == 0
+	}
+	built := r.FieldByName("r")
+	if !built.IsValid() || built.Kind() != reflect.Interface || built.IsNil() || built.Elem().Kind() != reflect.Ptr {
+		return nil, false
+	}
+	built = built.Elem().Elem()
+	var pairs []string
+	switch built.Type().String() {
+	case "strings.byteReplacer":
+		for i := 0; i < built.Len(); i++ {
+			if b := byte(built.Index(i).Uint()); b != byte(i) {
+				pairs = append(pairs, string([]byte{byte(i)}), string([]byte{b}))
+			}
+		}
+	case "strings.byteStringReplacer":
+		replacements := built.FieldByName("replacements")
+		if !replacements.IsValid() || replacements.Kind() != reflect.Array {
+			return nil, false
+		}
+		for i := 0; i < replacements.Len(); i++ {
+			if b := replacements.Index(i); !b.IsNil() {
+				pairs = append(pairs, string([]byte{byte(i)}), string(b.Bytes()))
+			}
+		}
+	default:
+		return nil, false
+	}
+	return pairs, len(pairs) > 0
+}
@@ -0,0 +1,50 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WithInteractiveResolver sets the func obtaining values of required fields left unset after unmarshaling,
+// e.g. prompting the user of CLI tool. It's called with the config key of every field with `required` tag option
+// or `validate:"required"` tag and, with WithRequireAllFields, of every field that isn't optional, in order of keys.
+// The value is decoded into the field just like env variables are, the empty one leaves the field unset.
+func (e *Enviper) WithInteractiveResolver(resolve func(path string) (string, error)) *Enviper {
+	e.resolver = resolve
+	return e
+}
+
+// resolveMissing sets required fields left unset with values obtained by the resolver
+func (e *Enviper) resolveMissing(rawVal interface{}) error {
+	missing := e.missingRequired(reflect.ValueOf(rawVal), nil, e.requiredAt)
+	if e.requireAllFields {
+		missing = append(missing, e.zeroFields(reflect.ValueOf(rawVal), nil)...)
+	}
+	sort.Strings(missing)
+
+	for i, path := range missing {
+		if i > 0 && path == missing[i-1] {
+			continue
+		}
+		val, err := e.resolver(path)
+		if err != nil {
+			return fmt.Errorf("can't resolve %s: %s", path, err)
+		}
+		if val == "" {
+			continue
+		}
+		err = e.postDecode(reflect.ValueOf(rawVal), strings.Split(path, "."), func(current interface{}) (interface{}, error) {
+			out := reflect.New(reflect.TypeOf(current))
+			if err := decode(val, e.decoderConfig(out.Interface())); err != nil {
+				return nil, err
+			}
+			return out.Elem().Interface(), nil
+		})
+		if err != nil {
+			return fmt.Errorf("can't resolve %s: %s", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package enviper_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInteractiveResolver(t *testing.T) {
+	e, cleanup := exactEnviper(t, `
+region: eu
+servers:
+  - name: a
+  - {}
+`)
+	defer cleanup()
+
+	var asked []string
+	e.WithInteractiveResolver(func(path string) (string, error) {
+		asked = append(asked, path)
+		return map[string]string{"db_host": "db.local", "servers.1.name": "b"}[path], nil
+	})
+
+	var c ExactConfig
+	if assert.Nil(t, e.UnmarshalExact(&c)) {
+		assert.Equal(t, []string{"db_host", "servers.1.name"}, asked)
+		assert.Equal(t, "db.local", c.DBHost)
+		assert.Equal(t, "b", c.Servers[1].Name)
+	}
+}
+
+func TestInteractiveResolverRequireAllFields(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_NAME":    "app",
+		"APP_LEVEL":   "debug",
+		"APP_DB_HOST": "localhost",
+	})()
+
+	e := enviper.New(viper.New()).WithRequireAllFields().WithInteractiveResolver(func(path string) (string, error) {
+		return map[string]string{"port": "8080", "Tags": "a,b"}[path], nil
+	})
+	e.SetEnvPrefix("APP")
+
+	var c RequireAllConfig
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		// the resolver left DB.User empty
+		assert.Equal(t, "fields are not set: DB.User", err.Error())
+	}
+	assert.Equal(t, 8080, c.Port)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+}
+
+func TestInteractiveResolverErrors(t *testing.T) {
+	e, cleanup := exactEnviper(t, `region: eu`)
+	defer cleanup()
+
+	var c ExactConfig
+	e.WithInteractiveResolver(func(path string) (string, error) {
+		return "", errors.New("no terminal")
+	})
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "can't resolve db_host: no terminal", err.Error())
+	}
+
+	var p struct {
+		Port int `mapstructure:"port,required"`
+	}
+	e.WithInteractiveResolver(func(path string) (string, error) {
+		return "not a number", nil
+	})
+	err = e.Unmarshal(&p)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't resolve port")
+	}
+}
@@ -0,0 +1,148 @@
+package enviper
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// JSONSchema returns the JSON Schema of the config, e.g. for autocompletion and validation of config files in editors.
+// Properties are the lowercased keys fields have in the config file, descriptions are taken from `doc` tags
+// and allowed values from `oneof` tags. Fields with `required` tag option are required,
+// with WithRequireAllFields all fields that are not optional are.
+// Durations, time zones and custom types are strings, as they are set by strings.
+func (e *Enviper) JSONSchema(rawVal interface{}) ([]byte, error) {
+	t := reflect.TypeOf(rawVal)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct && t.Kind() != reflect.Map {
+		return nil, fmt.Errorf("can't build JSON schema of %T, it's not a struct or map", rawVal)
+	}
+	schema := e.typeSchema(t, map[reflect.Type]bool{})
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the schema of values of the type, recursive types are left unconstrained where they recur
+func (e *Enviper) typeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if e.isLeaf(t) || t.Kind() != reflect.String && (t.Implements(textMarshalerType) || t.Implements(stringerType)) {
+		schema := map[string]interface{}{"type": "string"}
+		if t == timeType {
+			schema["format"] = "date-time"
+		}
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]interface{}{}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		properties := map[string]interface{}{}
+		var required []string
+		e.structSchema(t, seen, properties, &required)
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+		return schema
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": e.typeSchema(t.Elem(), seen)}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{"type": "array", "items": e.typeSchema(t.Elem(), seen)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema adds schemas of fields of the struct to properties by their keys, squashed fields are flattened
+func (e *Enviper) structSchema(t reflect.Type, seen map[reflect.Type]bool, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" || e.isSkipped(sf.Type) || sf.PkgPath != "" && !e.setterBinding {
+			continue
+		}
+		if e.isSquashed(sf, name, opts) {
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				e.structSchema(ft, seen, properties, required)
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		key := strings.ToLower(name)
+
+		schema := e.typeSchema(sf.Type, seen)
+		if doc := sf.Tag.Get("doc"); doc != "" {
+			schema["description"] = doc
+		}
+		if allowed := strings.Fields(sf.Tag.Get("oneof")); len(allowed) > 0 {
+			if items, ok := schema["items"].(map[string]interface{}); ok {
+				items["enum"] = enum(items, allowed)
+			} else {
+				schema["enum"] = enum(schema, allowed)
+			}
+		}
+		properties[key] = schema
+		if isRequired(sf, opts) || e.requireAllFields && !opts.has("omitempty") && sf.Type.Kind() != reflect.Ptr {
+			*required = append(*required, key)
+		}
+	}
+}
+
+// enum converts allowed values to the type of the schema, values that can't be converted are left as strings
+func enum(schema map[string]interface{}, allowed []string) []interface{} {
+	values := make([]interface{}, len(allowed))
+	for i, a := range allowed {
+		values[i] = a
+		switch schema["type"] {
+		case "integer":
+			if n, err := strconv.ParseInt(a, 10, 64); err == nil {
+				values[i] = n
+			}
+		case "number":
+			if n, err := strconv.ParseFloat(a, 64); err == nil {
+				values[i] = n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(a); err == nil {
+				values[i] = b
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,89 @@
+package enviper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type SchemaServer struct {
+	Host string `mapstructure:",required" doc:"host name"`
+	Port int    `oneof:"80 443"`
+}
+
+type SchemaBase struct {
+	Debug bool
+}
+
+type SchemaConfig struct {
+	SchemaBase `mapstructure:",squash"`
+	Level      string        `mapstructure:"level" oneof:"debug info" doc:"log level"`
+	Timeout    time.Duration `mapstructure:",omitempty"`
+	StartedAt  *time.Time    `mapstructure:"started_at"`
+	Ratio      float64
+	Codes      []int             `oneof:"200 204"`
+	Servers    []SchemaServer    `doc:"upstreams"`
+	Labels     map[string]string `mapstructure:"labels,omitempty"`
+	Next       *SchemaConfig
+	Ignored    string `mapstructure:"-"`
+	hidden     string
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema, err := enviper.New(viper.New()).WithRequireAllFields().JSONSchema(&SchemaConfig{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"debug": {"type": "boolean"},
+			"level": {"type": "string", "enum": ["debug", "info"], "description": "log level"},
+			"timeout": {"type": "string"},
+			"started_at": {"type": "string", "format": "date-time"},
+			"ratio": {"type": "number"},
+			"codes": {"type": "array", "items": {"type": "integer", "enum": [200, 204]}},
+			"servers": {
+				"type": "array",
+				"description": "upstreams",
+				"items": {
+					"type": "object",
+					"properties": {
+						"host": {"type": "string", "description": "host name"},
+						"port": {"type": "integer", "enum": [80, 443]}
+					},
+					"required": ["host", "port"]
+				}
+			},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"next": {}
+		},
+		"required": ["codes", "debug", "level", "ratio", "servers"]
+	}`, string(schema))
+}
+
+func TestJSONSchemaWithoutRequiredFields(t *testing.T) {
+	schema, err := enviper.New(viper.New()).JSONSchema(SchemaServer{})
+	if assert.Nil(t, err) {
+		assert.JSONEq(t, `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type": "object",
+			"properties": {
+				"host": {"type": "string", "description": "host name"},
+				"port": {"type": "integer", "enum": [80, 443]}
+			},
+			"required": ["host"]
+		}`, string(schema))
+	}
+}
+
+func TestJSONSchemaOfScalar(t *testing.T) {
+	_, err := enviper.New(viper.New()).JSONSchema(1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "can't build JSON schema of int, it's not a struct or map", err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package enviper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// secretFileSuffix is the suffix of env variables with paths of files with values, e.g. Docker secrets
+const secretFileSuffix = "_FILE"
+
+// secretFileScheme is the prefix of values that are paths of files with values
+const secretFileScheme = "file://"
+
+// WithSecretFiles makes fields accept paths of files with their values, just like Docker and Kubernetes secrets are passed:
+// `MYAPP_DB_PASSWORD_FILE=/run/secrets/db_password` sets the field from the file, taking precedence over `MYAPP_DB_PASSWORD`,
+// and so does the value `file:///run/secrets/db_password` of either env variable or config file.
+// The trailing newline of the file is trimmed.
+func (e *Enviper) WithSecretFiles() *Enviper {
+	e.secretFiles = true
+	return e
+}
+
+// readSecretFiles collects values of fields read from files by env variables with `_FILE` suffix to overrides
+func (e *Enviper) readSecretFiles(rawVal interface{}, prev ...string) error {
+	if !e.secretFiles {
+		return nil
+	}
+	var errs []string
+	e.walk(field{path: prev, env: prev, value: reflect.ValueOf(rawVal)}, func(f field) {
+		if e.failed(errs) || f.value.Kind() == reflect.Map && !e.isLeaf(f.value.Type()) {
+			return
+		}
+		env := e.suffixedEnvName(f, secretFileSuffix)
+		path, ok := e.lookupEnv(env)
+		if !ok || path == "" {
+			return
+		}
+		val, err := readSecretFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", env, err))
+			return
+		}
+		e.overrides[strings.Join(f.path, ".")] = val
+	})
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("can't read secret files: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// secretFileHook replaces values like `file:///run/secrets/db_password` with the contents of the files
+func secretFileHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String {
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	if !strings.HasPrefix(raw, secretFileScheme) {
+		return data, nil
+	}
+	return readSecretFile(raw[len(secretFileScheme):])
+}
+
+func readSecretFile(path string) (string, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(raw), "\n"), "\r"), nil
+}
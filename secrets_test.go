@@ -0,0 +1,86 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type SecretsConfig struct {
+	Name string
+	DB   struct {
+		User     string
+		Password string
+		Port     int
+	}
+	APIKey string `mapstructure:"api_key"`
+}
+
+func TestSecretFiles(t *testing.T) {
+	dir, cleanup := writeConfig(t, "")
+	defer cleanup()
+	writeJSON(t, dir, "config.yaml", `
+name: app
+api_key: file://`+writeJSON(t, dir, "api_key", "key\n"))
+	defer setenv(t, map[string]string{
+		"APP_DB_PASSWORD_FILE": writeJSON(t, dir, "db_password", "s3cret\n"),
+		"APP_DB_PASSWORD":      "ignored",
+		"APP_DB_USER":          "file://" + writeJSON(t, dir, "db_user", "admin"),
+		"APP_DB_PORT_FILE":     writeJSON(t, dir, "db_port", "5432\r\n"),
+	})()
+
+	var c SecretsConfig
+	e := enviper.New(viper.New()).WithSecretFiles()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "app", c.Name)
+		assert.Equal(t, "s3cret", c.DB.Password)
+		assert.Equal(t, "admin", c.DB.User)
+		assert.Equal(t, 5432, c.DB.Port)
+		assert.Equal(t, "key", c.APIKey)
+	}
+	assert.Empty(t, e.LintEnv(&c))
+}
+
+func TestSecretFilesMissing(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_DB_PASSWORD_FILE": "/nonexistent/db_password",
+		"APP_DB_USER":          "file:///nonexistent/db_user",
+	})()
+
+	var c SecretsConfig
+	e := enviper.New(viper.New()).WithSecretFiles()
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't read secret files: APP_DB_PASSWORD_FILE: open /nonexistent/db_password")
+	}
+
+	defer setenv(t, map[string]string{"APP_DB_PASSWORD_FILE": ""})()
+	err = e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "open /nonexistent/db_user")
+	}
+}
+
+func TestSecretFilesDisabled(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_DB_PASSWORD_FILE": "/run/secrets/db_password",
+		"APP_DB_USER":          "file:///run/secrets/db_user",
+	})()
+
+	var c SecretsConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "", c.DB.Password)
+		assert.Equal(t, "file:///run/secrets/db_user", c.DB.User)
+	}
+}
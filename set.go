@@ -0,0 +1,73 @@
+package enviper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// isSet reports whether the field is `map[string]bool` with `set` tag option (e.g. `mapstructure:"features,set"`),
+// that is set by the list of its keys, e.g. `MYAPP_FEATURES="a b c"` or `MYAPP_FEATURES=a,b,c`
+func (e *Enviper) isSet(f field) bool {
+	return f.opts.has("set") && f.value.Kind() == reflect.Map && f.value.Type().Elem().Kind() == reflect.Bool
+}
+
+// overrideSet collects the value of env variable bound to the set
+func (e *Enviper) overrideSet(f field) error {
+	val, ok := e.lookupEnv(e.fieldEnvName(f))
+	if !ok || val == "" {
+		return nil
+	}
+	set, err := parseSet(val)
+	if err != nil {
+		return fmt.Errorf("can't parse %s: %s", e.fieldEnvName(f), err)
+	}
+	e.overrides[strings.Join(f.path, ".")] = set
+	return nil
+}
+
+// parseSet parses the list of keys separated by spaces or commas, JSON objects are decoded as is
+func parseSet(raw string) (map[string]interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	set := map[string]interface{}{}
+	if strings.HasPrefix(raw, "{") {
+		err := json.Unmarshal([]byte(raw), &set)
+		return set, err
+	}
+	for _, key := range strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		set[key] = true
+	}
+	return set, nil
+}
+
+// setFromList returns the set from the list of its keys in config file merged with keys set by env variables,
+// or nil when it's not a list
+func (e *Enviper) setFromList(settings map[string]interface{}, path []string) map[string]interface{} {
+	list, ok := e.Viper.Get(strings.Join(path, ".")).([]interface{})
+	if !ok {
+		return nil
+	}
+	set := make(map[string]interface{}, len(list))
+	for _, key := range list {
+		set[fmt.Sprint(key)] = true
+	}
+	if current, ok := getPath(settings, path).(map[string]interface{}); ok {
+		for key, val := range current {
+			set[key] = val
+		}
+	}
+	// settings have either the list or keys set by env variables, depending on order of keys
+	prefix := strings.ToLower(strings.Join(path, ".")) + "."
+	for _, key := range e.Viper.AllKeys() {
+		if strings.HasPrefix(key, prefix) && !strings.Contains(key[len(prefix):], ".") {
+			if val := e.Viper.Get(key); val != nil {
+				set[key[len(prefix):]] = val
+			}
+		}
+	}
+	return set
+}
@@ -0,0 +1,66 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type SetConfig struct {
+	Features map[string]bool `mapstructure:"features,set"`
+	Flags    map[string]bool `mapstructure:"flags"`
+}
+
+func TestSetFromList(t *testing.T) {
+	for raw, expected := range map[string]map[string]bool{
+		"a b c":                {"a": true, "b": true, "c": true},
+		"a,b, c":               {"a": true, "b": true, "c": true},
+		" a\tb ":               {"a": true, "b": true},
+		`{"a":true,"b":false}`: {"a": true, "b": false},
+	} {
+		func() {
+			defer setenv(t, map[string]string{"APP_FEATURES": raw})()
+
+			var c SetConfig
+			e := enviper.New(viper.New())
+			e.SetEnvPrefix("APP")
+			if assert.Nil(t, e.Unmarshal(&c), raw) {
+				assert.Equal(t, expected, c.Features, raw)
+			}
+		}()
+	}
+}
+
+func TestSetFromFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+features: [a, b]
+flags:
+  x: true
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_FEATURES_C": "true", "APP_FEATURES_A": "false"})()
+
+	var c SetConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, map[string]bool{"a": false, "b": true, "c": true}, c.Features)
+		assert.Equal(t, map[string]bool{"x": true}, c.Flags)
+	}
+}
+
+func TestSetInvalidJSON(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_FEATURES": `{"a":`})()
+
+	var c SetConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't parse APP_FEATURES: ")
+	}
+}
@@ -0,0 +1,102 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/viper"
+)
+
+// WithSetterBinding makes Unmarshal set unexported fields by calling their exported setters,
+// e.g. field `port` is set with `SetPort(int)` or `SetPort(int) error` method of the struct pointer.
+// Unexported fields without setters are left untouched.
+func (e *Enviper) WithSetterBinding() *Enviper {
+	e.setterBinding = true
+	return e
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// applySetters calls setters of unexported fields with values from settings
+func (e *Enviper) applySetters(v reflect.Value, settings interface{}, opts ...viper.DecoderConfigOption) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !v.CanAddr() || e.isLeaf(v.Type()) {
+		return nil
+	}
+
+	var errs []string
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name, tagOpts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" {
+			continue
+		}
+		if e.isSquashed(sf, name, tagOpts) {
+			if err := e.applySetters(v.Field(i), settings, opts...); err != nil {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		value, ok := lookupKey(settings, name)
+		if !ok {
+			continue
+		}
+
+		if sf.PkgPath == "" {
+			if err := e.applySetters(v.Field(i), value, opts...); err != nil {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+
+		runes := []rune(sf.Name)
+		runes[0] = unicode.ToUpper(runes[0])
+		setter := v.Addr().MethodByName("Set" + string(runes))
+		if !setter.IsValid() || setter.Type().NumIn() != 1 || setter.Type().NumOut() > 1 ||
+			setter.Type().NumOut() == 1 && setter.Type().Out(0) != errorType {
+			continue
+		}
+		arg := reflect.New(setter.Type().In(0))
+		if err := decode(value, e.decoderConfig(arg.Interface(), opts...)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", sf.Name, err))
+			continue
+		}
+		out := setter.Call([]reflect.Value{arg.Elem()})
+		if len(out) == 1 && !out[0].IsNil() {
+			errs = append(errs, fmt.Sprintf("%s: %s", sf.Name, out[0].Interface()))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("can't set fields of %s: %s", v.Type(), strings.Join(e.firstOnly(errs), "; "))
+	}
+	return nil
+}
+
+// lookupKey returns the value of the key in settings map, keys are compared case insensitively
+func lookupKey(settings interface{}, key string) (interface{}, bool) {
+	switch m := settings.(type) {
+	case map[string]interface{}:
+		for k, v := range m {
+			if strings.EqualFold(k, key) {
+				return v, true
+			}
+		}
+	case map[interface{}]interface{}:
+		for k, v := range m {
+			if strings.EqualFold(fmt.Sprint(k), key) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
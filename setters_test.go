@@ -0,0 +1,93 @@
+package enviper_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type EncapsulatedConfig struct {
+	Name     string
+	port     int
+	hosts    []string
+	internal string
+	Nested   struct {
+		Inner EncapsulatedInner
+	}
+}
+
+func (c *EncapsulatedConfig) SetPort(port int) error {
+	if port <= 0 {
+		return errors.New("port must be positive")
+	}
+	c.port = port
+	return nil
+}
+
+func (c *EncapsulatedConfig) SetHosts(hosts []string) {
+	c.hosts = hosts
+}
+
+type EncapsulatedInner struct {
+	level string
+}
+
+func (i *EncapsulatedInner) SetLevel(level string) {
+	i.level = "level:" + level
+}
+
+func TestSetterBinding(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+hosts: [a, b]
+nested:
+  inner:
+    level: info
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_NAME":               "app",
+		"APP_PORT":               "8080",
+		"APP_INTERNAL":           "ignored",
+		"APP_NESTED_INNER_LEVEL": "debug",
+	})()
+
+	var c EncapsulatedConfig
+	e := enviper.New(viper.New()).WithSetterBinding()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "app", c.Name)
+	assert.Equal(t, 8080, c.port)
+	assert.Equal(t, []string{"a", "b"}, c.hosts)
+	assert.Equal(t, "", c.internal)
+	assert.Equal(t, "level:debug", c.Nested.Inner.level)
+}
+
+func TestSetterBindingDisabled(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PORT": "8080"})()
+
+	var c EncapsulatedConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, 0, c.port)
+}
+
+func TestSetterBindingErrors(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PORT": "-1"})()
+
+	var c EncapsulatedConfig
+	e := enviper.New(viper.New()).WithSetterBinding()
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "can't set fields of enviper_test.EncapsulatedConfig: port: port must be positive", err.Error())
+	}
+}
@@ -0,0 +1,29 @@
+package enviper
+
+import (
+	"reflect"
+)
+
+// stdlibInternals are packages of types that have only unexported internals, so they are never configured
+var stdlibInternals = map[string]bool{
+	"sync":        true,
+	"sync/atomic": true,
+}
+
+// WithSkipStdlibInternals makes fields of types from `sync` and `sync/atomic` packages
+// (e.g. embedded `sync.Mutex` guarding the config) ignored, so their internals are not bound to env variables.
+func (e *Enviper) WithSkipStdlibInternals() *Enviper {
+	e.skipStdlibInternals = true
+	return e
+}
+
+// isSkipped reports whether fields of the type are ignored
+func (e *Enviper) isSkipped(t reflect.Type) bool {
+	if !e.skipStdlibInternals {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return stdlibInternals[t.PkgPath()]
+}
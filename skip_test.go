@@ -0,0 +1,50 @@
+package enviper_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type GuardedConfig struct {
+	sync.Mutex
+	Once  *sync.Once
+	Count atomic.Value
+	Name  string
+}
+
+func TestSkipStdlibInternals(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_NAME":           "app",
+		"APP_MUTEX_MU_STATE": "1",
+		"APP_ONCE_DONE":      "1",
+	})()
+
+	c := &GuardedConfig{}
+	e := enviper.New(viper.New()).WithSkipStdlibInternals()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(c))
+	assert.Equal(t, "app", c.Name)
+	assert.Nil(t, c.Once)
+	assert.Equal(t, []string{"name"}, e.AllKeys())
+	assert.Equal(t, []string{"APP_NAME"}, e.ConsumedEnvVars(c))
+
+	// the mutex is still usable
+	c.Lock()
+	c.Unlock()
+}
+
+func TestStdlibInternalsBoundByDefault(t *testing.T) {
+	var c GuardedConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	// the keys depend on the Go version, as they are internals
+	assert.True(t, len(e.AllKeys()) > 1)
+}
@@ -0,0 +1,195 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// WithNumberedSlices makes slices of scalars collect numbered env variables in numeric order,
+// e.g. `MYAPP_TAGS_1=a` and `MYAPP_TAGS_3=b` produce `[]string{"a", "b"}`.
+// Numbers are not indexes, so gaps are skipped and the whole slice from config file is replaced.
+// Slices of structs are still set by indexes.
+func (e *Enviper) WithNumberedSlices() *Enviper {
+	e.numberedSlices = true
+	return e
+}
+
+// isNumbered reports whether the slice type is collected from numbered env variables
+func (e *Enviper) isNumbered(t reflect.Type) bool {
+	if !e.numberedSlices || t.Kind() != reflect.Slice {
+		return false
+	}
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	switch et.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return e.isLeaf(et)
+	default:
+		return true
+	}
+}
+
+// overrideNumbered collects values of numbered env variables of the slice in numeric order
+func (e *Enviper) overrideNumbered(f field) {
+	var values []interface{}
+	for _, n := range e.envIndexes(f.env) {
+		if val, ok := e.lookupEnv(e.envName(appendPath(f.env, strconv.Itoa(n)))); ok && val != "" {
+			values = append(values, val)
+		}
+	}
+	if len(values) > 0 {
+		e.overrides[strings.Join(f.path, ".")] = values
+	}
+}
+
+// WithIndexedArrays makes elements of arrays set one by one by their indexes like elements of slices,
+// e.g. `MYAPP_COLOR_0=255` for `Color [3]uint8`. They are applied on top of the array set as a whole,
+// indexes out of the array bounds are ignored.
+func (e *Enviper) WithIndexedArrays() *Enviper {
+	e.indexedArrays = true
+	return e
+}
+
+// WithSliceIndexFormat sets the func deriving the env variable name of slice element
+// from the env variable name of the slice (e.g. `MYAPP_SERVERS`) and the index of element.
+// By default it's `base + "_" + index`, so fields of elements are set by `MYAPP_SERVERS_0_HOST`.
+// For legacy names like `MYAPP_SERVERS0_HOST` use:
+//
+//	e.WithSliceIndexFormat(func(base string, i int) string {
+//		return base + strconv.Itoa(i)
+//	})
+//
+// Numeric keys of maps are treated as indexes too.
+func (e *Enviper) WithSliceIndexFormat(format func(base string, i int) string) *Enviper {
+	e.sliceIndexFormat = format
+	return e
+}
+
+// formattedEnvName does the same as prefixedEnvName, but numeric segments of the path are formatted with sliceIndexFormat
+func (e *Enviper) formattedEnvName(prefix string, path []string) string {
+	name := e.envKey(prefix)
+	for i, segment := range path {
+		if n, err := strconv.Atoi(segment); err == nil && i > 0 {
+			name = e.sliceIndexFormat(name, n)
+			continue
+		}
+		segment = e.envKey(segment)
+		if name == "" {
+			name = segment
+		} else {
+			name += e.separator() + segment
+		}
+	}
+	return name
+}
+
+var digits = regexp.MustCompile(`[0-9]+`)
+
+// maxSliceIndex is the largest index of slice element set by env variable, e.g. `PREFIX_SLICE_9999`.
+// Env variables with larger indexes are ignored, so a typo in the name doesn't allocate a huge slice.
+const maxSliceIndex = 9999
+
+// sliceIndex parses the index of slice element, numbers out of range are not indexes
+func sliceIndex(s string) (int, bool) {
+	i, err := strconv.Atoi(s)
+	return i, err == nil && i >= 0 && i <= maxSliceIndex
+}
+
+// formattedEnvIndexes does the same as envIndexes, but with names of elements formatted with sliceIndexFormat.
+// As the format can't be reversed, every number found in env variable name is tried as an index.
+func (e *Enviper) formattedEnvIndexes(path []string) []int {
+	seen := map[int]bool{}
+	var indexes []int
+	for _, kv := range e.environ() {
+		env := kv[:strings.Index(kv, "=")]
+		for _, number := range digits.FindAllString(env, -1) {
+			i, ok := sliceIndex(number)
+			if !ok || seen[i] {
+				continue
+			}
+			elem := appendPath(path, strconv.Itoa(i))
+			if _, nested := e.envRest(env, elem); nested || env == e.envName(elem) {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// expandSlices replaces string values of slices the path is nested in with their elements,
+// so elements set by indexed env variables are applied on top of the slice set as a whole,
+// e.g. `MYAPP_TAGS=a,b` with `MYAPP_TAGS_1=z` is `[a z]`
+func (e *Enviper) expandSlices(settings map[string]interface{}, path []string, opts ...viper.DecoderConfigOption) (map[string]interface{}, error) {
+	for i := 1; i < len(path); i++ {
+		t, ok := e.sliceTypes[strings.Join(path[:i], ".")]
+		if !ok {
+			continue
+		}
+		raw, ok := getPath(settings, path[:i]).(string)
+		if !ok {
+			continue
+		}
+		out, err := mapstructure.DecodeHookExec(e.decoderConfig(nil, opts...).DecodeHook, reflect.TypeOf(raw), t, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", strings.Join(path[:i], "."), err)
+		}
+		list := reflect.ValueOf(out)
+		if list.Kind() != reflect.Slice {
+			continue
+		}
+		elems := make([]interface{}, list.Len())
+		for j := range elems {
+			elems[j] = list.Index(j).Interface()
+		}
+		settings = setPath(settings, path[:i], elems).(map[string]interface{})
+	}
+	return settings, nil
+}
+
+// getPath returns the value of settings by the path, keys of maps are matched case insensitively
+func getPath(node interface{}, path []string) interface{} {
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			next, ok := n[key]
+			if !ok {
+				for nk, nv := range n {
+					if strings.EqualFold(nk, key) {
+						next = nv
+					}
+				}
+			}
+			node = next
+		case map[interface{}]interface{}:
+			next, ok := n[key]
+			if !ok {
+				for nk, nv := range n {
+					if strings.EqualFold(fmt.Sprint(nk), key) {
+						next = nv
+					}
+				}
+			}
+			node = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil
+			}
+			node = n[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
@@ -0,0 +1,524 @@
+package enviper_test
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberedSlices(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+tags: [x, y, z, w]
+servers:
+  - host: first
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_TAGS_1":             "a",
+		"APP_TAGS_3":             "b",
+		"APP_TAGS_10":            "c",
+		"APP_PORTS_2":            "443",
+		"APP_SERVERS_1_HOST":     "second",
+		"APP_SERVERS_1_TLS_CA_5": "ca5",
+	})()
+
+	var c struct {
+		Tags    []string
+		Ports   []int
+		Servers []Server
+	}
+	e := enviper.New(viper.New()).WithNumberedSlices()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"a", "b", "c"}, c.Tags)
+	assert.Equal(t, []int{443}, c.Ports)
+	if assert.Len(t, c.Servers, 2) {
+		assert.Equal(t, "first", c.Servers[0].Host)
+		assert.Equal(t, "second", c.Servers[1].Host)
+		assert.Equal(t, []string{"ca5"}, c.Servers[1].TLS.CA)
+	}
+	assert.Empty(t, e.LintEnv(&c))
+}
+
+func TestNumberedSlicesFallbackToWholeSlice(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_TAGS": "a,b"})()
+
+	var c struct {
+		Tags []string
+	}
+	e := enviper.New(viper.New()).WithNumberedSlices()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+}
+
+func TestIndexedSlicesKeepGaps(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TAGS_1": "a",
+		"APP_TAGS_3": "b",
+	})()
+
+	var c struct {
+		Tags []string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"", "a", "", "b"}, c.Tags)
+}
+
+func TestIndexedSlicesIgnoreHugeIndexes(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TAGS_1":                   "a",
+		"APP_TAGS_10000":               "b",
+		"APP_TAGS_9223372036854775806": "c",
+		"APP_SERVERS_99999999_HOST":    "d",
+	})()
+
+	var c struct {
+		Tags    []string
+		Servers []Server
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"", "a"}, c.Tags)
+	assert.Empty(t, c.Servers)
+}
+
+func TestIndexedSlicesAfterPrefixIsCleared(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TAGS_0": "prefixed",
+		"TAGS_1":     "b",
+	})()
+
+	var c struct {
+		Tags []string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.SetEnvPrefix("")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"", "b"}, c.Tags)
+}
+
+func TestIndexedSliceOfStructsInNumericOrder(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+servers:
+  - host: first
+    tls:
+      cert: first.crt
+`)
+	defer cleanup()
+	env := map[string]string{"APP_SERVERS_11_TLS_CA_0": "last.ca"}
+	for i := 0; i < 12; i++ {
+		env["APP_SERVERS_"+strconv.Itoa(i)+"_HOST"] = "host" + strconv.Itoa(i)
+	}
+	defer setenv(t, env)()
+
+	var c SlicesConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) && assert.Len(t, c.Servers, 12) {
+		for i, server := range c.Servers {
+			assert.Equal(t, "host"+strconv.Itoa(i), server.Host)
+		}
+		assert.Equal(t, "first.crt", c.Servers[0].TLS.Cert)
+		assert.Equal(t, []string{"last.ca"}, c.Servers[11].TLS.CA)
+	}
+}
+
+func TestWholeSliceReplacesLongerSliceFromFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, "tags: [x, y, z]")
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_TAGS": "a"})()
+
+	var c struct {
+		Tags []string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"a"}, c.Tags)
+}
+
+func TestSliceIndexFormat(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+servers:
+  - host: first
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_SERVERS0_TLS_CERT": "first.crt",
+		"APP_SERVERS2_HOST":     "third",
+		"APP_SERVERS2_TLS_CA1":  "ca1",
+		"APP_SERVERS_1_HOST":    "ignored",
+		"APP_TAGS1":             "b",
+	})()
+
+	var c SlicesConfig
+	e := enviper.New(viper.New()).WithSliceIndexFormat(func(base string, i int) string {
+		return base + strconv.Itoa(i)
+	})
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	if assert.Len(t, c.Servers, 3) {
+		assert.Equal(t, "first", c.Servers[0].Host)
+		assert.Equal(t, "first.crt", c.Servers[0].TLS.Cert)
+		assert.Equal(t, "", c.Servers[1].Host)
+		assert.Equal(t, "third", c.Servers[2].Host)
+		assert.Equal(t, []string{"", "ca1"}, c.Servers[2].TLS.CA)
+	}
+	assert.Equal(t, []string{"", "b"}, c.Tags)
+
+	env, err := e.MarshalEnv(&SlicesConfig{Tags: []string{"a"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"APP_TAGS0": "a"}, env)
+}
+
+func TestSliceIndexFormatNumbered(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TAGS[3]": "b",
+		"APP_TAGS[1]": "a",
+	})()
+
+	var c struct {
+		Tags []string
+	}
+	e := enviper.New(viper.New()).WithNumberedSlices().WithSliceIndexFormat(func(base string, i int) string {
+		return base + "[" + strconv.Itoa(i) + "]"
+	})
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+	assert.Equal(t, []string{"APP_TAGS[1]", "APP_TAGS[3]"}, e.ConsumedEnvVars(&c))
+}
+
+// Unmarshal never sets defaults of viper, so getters keep returning values from config file and env variables
+// of whole slices, while indexed env variables are applied to the unmarshaled value only
+func TestSlicesDontChangeViperDefaults(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+tags: [one, two]
+hosts: [a, b]
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_HOSTS":  "c,d",
+		"APP_TAGS_1": "z",
+	})()
+
+	var c struct {
+		Tags  []string
+		Hosts []string
+		Ports []string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, []string{"one", "z"}, c.Tags)
+	assert.Equal(t, []string{"c", "d"}, c.Hosts)
+
+	assert.Equal(t, []string{"one", "two"}, e.GetStringSlice("tags"))
+	// viper splits env values by spaces, not by commas
+	assert.Equal(t, []string{"c,d"}, e.GetStringSlice("hosts"))
+	assert.False(t, e.IsSet("ports"))
+	assert.Empty(t, e.GetStringSlice("ports"))
+}
+
+func TestPointerToSliceOfPointersToStructs(t *testing.T) {
+	type Server struct {
+		Host string
+		Port int
+	}
+	var c struct {
+		Servers *[]*Server
+	}
+	defer setenv(t, map[string]string{
+		"APP_SERVERS_0_HOST": "x",
+		"APP_SERVERS_0_PORT": "80",
+		"APP_SERVERS_1_HOST": "y",
+	})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) && assert.NotNil(t, c.Servers) {
+		assert.Equal(t, []*Server{{Host: "x", Port: 80}, {Host: "y"}}, *c.Servers)
+	}
+}
+
+func TestPointerToSliceOfPointersToStructsOverFile(t *testing.T) {
+	type Server struct {
+		Host string
+		Port int
+	}
+	var c struct {
+		Servers *[]*Server
+	}
+	dir, cleanup := writeConfig(t, `
+servers:
+  - host: a
+    port: 1
+  - host: b
+    port: 2
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_SERVERS_1_HOST": "y"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+	if assert.Nil(t, e.Unmarshal(&c)) && assert.NotNil(t, c.Servers) {
+		assert.Equal(t, []*Server{{Host: "a", Port: 1}, {Host: "y", Port: 2}}, *c.Servers)
+	}
+}
+
+func TestIndexedSlicesDontChangeEnv(t *testing.T) {
+	dir, cleanup := writeConfig(t, "tags: [p, q, r]")
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"ONE_TAGS_0": "a",
+		"ONE_TAGS_1": "b",
+		"TWO_TAGS_2": "c",
+	})()
+	environ := func() []string {
+		env := os.Environ()
+		sort.Strings(env)
+		return env
+	}
+	before := environ()
+
+	var wg sync.WaitGroup
+	for prefix, expected := range map[string][]string{
+		"ONE": {"a", "b", "r"},
+		"TWO": {"p", "q", "c"},
+	} {
+		wg.Add(1)
+		go func(prefix string, expected []string) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				var c struct {
+					Tags []string
+				}
+				e := enviper.New(viper.New())
+				e.SetEnvPrefix(prefix)
+				e.AddConfigPath(dir)
+				e.SetConfigName("config")
+				if assert.Nil(t, e.Unmarshal(&c), prefix) {
+					assert.Equal(t, expected, c.Tags, prefix)
+				}
+			}
+		}(prefix, expected)
+	}
+	wg.Wait()
+
+	assert.Equal(t, before, environ())
+}
+
+func TestIndexedSlicesOfStructsConcurrently(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"ONE_SERVERS_0_HOST":     "one",
+		"ONE_SERVERS_1_TLS_CERT": "one.crt",
+		"TWO_SERVERS_0_TLS_CA_1": "two.ca",
+	})()
+	before := os.Environ()
+	sort.Strings(before)
+
+	var wg sync.WaitGroup
+	for _, prefix := range []string{"ONE", "TWO"} {
+		wg.Add(1)
+		go func(prefix string) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				var c SlicesConfig
+				v := viper.New()
+				e := enviper.New(v)
+				e.SetEnvPrefix(prefix)
+				if !assert.Nil(t, e.Unmarshal(&c), prefix) {
+					return
+				}
+				if prefix == "ONE" && assert.Len(t, c.Servers, 2) {
+					assert.Equal(t, "one", c.Servers[0].Host)
+					assert.Equal(t, "one.crt", c.Servers[1].TLS.Cert)
+				}
+				if prefix == "TWO" && assert.Len(t, c.Servers, 1) {
+					assert.Equal(t, []string{"", "two.ca"}, c.Servers[0].TLS.CA)
+				}
+				// overrides are applied while decoding, viper itself isn't changed
+				assert.Nil(t, v.Get("servers"), prefix)
+			}
+		}(prefix)
+	}
+	wg.Wait()
+
+	after := os.Environ()
+	sort.Strings(after)
+	assert.Equal(t, before, after)
+}
+
+func TestWholeSliceWithIndexedElements(t *testing.T) {
+	dir, cleanup := writeConfig(t, "items: [p, q, r, s]")
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_ITEMS":              `["a","b","c"]`,
+		"APP_ITEMS_1":            "z",
+		"APP_TAGS":               "a,b",
+		"APP_TAGS_3":             "d",
+		"APP_SERVERS":            `[{"host":"a","tls":{"key":"a.key"}},{"host":"b"}]`,
+		"APP_SERVERS_0_TLS_CERT": "a.crt",
+		"APP_SERVERS_1_HOST":     "y",
+	})()
+
+	var c struct {
+		Items   []string
+		Tags    []string
+		Servers []Server
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+	if !assert.Nil(t, e.Unmarshal(&c)) {
+		return
+	}
+	// indexed elements are applied on top of the whole slice, that replaces the one from config file
+	assert.Equal(t, []string{"a", "z", "c"}, c.Items)
+	assert.Equal(t, []string{"a", "b", "", "d"}, c.Tags)
+	if assert.Len(t, c.Servers, 2) {
+		assert.Equal(t, "a", c.Servers[0].Host)
+		assert.Equal(t, "a.key", c.Servers[0].TLS.Key)
+		assert.Equal(t, "a.crt", c.Servers[0].TLS.Cert)
+		assert.Equal(t, "y", c.Servers[1].Host)
+	}
+}
+
+func TestWholeSliceWithIndexedElementsInvalid(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_ITEMS":   `["a",`,
+		"APP_ITEMS_1": "z",
+	})()
+
+	var c struct {
+		Items []string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `Items: can't parse "[\"a\"," as JSON array`)
+	}
+}
+
+type RGB [3]uint8
+
+type ArrayConfig struct {
+	Color RGB
+	Names [2]string
+}
+
+func TestIndexedArrays(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+color: [10, 20, 30]
+names: [p, q]
+`)
+	defer cleanup()
+
+	for _, tc := range []struct {
+		env      map[string]string
+		expected ArrayConfig
+	}{
+		{
+			env:      map[string]string{},
+			expected: ArrayConfig{Color: RGB{10, 20, 30}, Names: [2]string{"p", "q"}},
+		},
+		{
+			env:      map[string]string{"APP_COLOR": "1,2,3", "APP_NAMES": `["a","b"]`},
+			expected: ArrayConfig{Color: RGB{1, 2, 3}, Names: [2]string{"a", "b"}},
+		},
+		{
+			env:      map[string]string{"APP_COLOR_0": "255", "APP_COLOR_2": "0", "APP_NAMES_1": "z"},
+			expected: ArrayConfig{Color: RGB{255, 20, 0}, Names: [2]string{"p", "z"}},
+		},
+		{
+			// indexed env variables are applied on top of the whole array, out of bounds ones are ignored
+			env:      map[string]string{"APP_COLOR": "1,2,3", "APP_COLOR_1": "7", "APP_NAMES": "a,b", "APP_NAMES_2": "c"},
+			expected: ArrayConfig{Color: RGB{1, 7, 3}, Names: [2]string{"a", "b"}},
+		},
+	} {
+		func() {
+			defer setenv(t, tc.env)()
+
+			var c ArrayConfig
+			e := enviper.New(viper.New()).WithIndexedArrays()
+			e.SetEnvPrefix("APP")
+			e.AddConfigPath(dir)
+			e.SetConfigName("config")
+			if assert.Nil(t, e.Unmarshal(&c), tc.env) {
+				assert.Equal(t, tc.expected, c, tc.env)
+			}
+		}()
+	}
+}
+
+func TestIndexedArraysDisabled(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_COLOR": "1,2,3", "APP_COLOR_0": "255"})()
+
+	var c ArrayConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, RGB{1, 2, 3}, c.Color)
+}
+
+func TestSliceOfJSONObjects(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_SERVERS":        `{"host": "a b", "tls": {"ca": ["x", "y z"]}}   {"host":"c"}`,
+		"APP_SERVERS_1_HOST": "d",
+		"APP_LABELS":         `{"name": "first label"} {"name": "second"}`,
+	})()
+
+	var c struct {
+		Servers []*Server
+		Labels  []map[string]string
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) && assert.Len(t, c.Servers, 2) {
+		assert.Equal(t, "a b", c.Servers[0].Host)
+		assert.Equal(t, []string{"x", "y z"}, c.Servers[0].TLS.CA)
+		assert.Equal(t, "d", c.Servers[1].Host)
+		assert.Equal(t, []map[string]string{{"name": "first label"}, {"name": "second"}}, c.Labels)
+	}
+
+	defer setenv(t, map[string]string{"APP_SERVERS": `{"host": "a"} {"host": `})()
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "as JSON objects")
+	}
+}
@@ -0,0 +1,64 @@
+package enviper
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Snapshot holds resolved values of the config by their keys, e.g. `db.host` or `servers.0.port`
+type Snapshot map[string]interface{}
+
+// Change describes a value that differs between two snapshots.
+// Old or New is nil when the key is missing in the corresponding snapshot, e.g. when the field is unset
+// or a slice got shorter.
+type Change struct {
+	Key string
+	Old interface{}
+	New interface{}
+}
+
+// Snapshot unmarshals the config to rawVal and captures its resolved values,
+// so they could be compared with DiffSnapshots after the config is reloaded.
+// Values that are not set are missing, except for elements of slices.
+func (e *Enviper) Snapshot(rawVal interface{}) (Snapshot, error) {
+	if err := e.Unmarshal(rawVal); err != nil {
+		return nil, err
+	}
+	s := Snapshot{}
+	e.walkElements(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		if kind := f.value.Kind(); (kind == reflect.Map || kind == reflect.Slice) && !e.isLeaf(f.value.Type()) {
+			return
+		}
+		if !f.indexed && !e.isValueSet(f.value) {
+			return
+		}
+		s[strings.Join(f.path, ".")] = valueInterface(f.value)
+	}, func(f field) []int {
+		indexes := make([]int, f.value.Len())
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	})
+	return s, nil
+}
+
+// DiffSnapshots returns changes between snapshots a and b sorted by keys
+func DiffSnapshots(a, b Snapshot) []Change {
+	var changes []Change
+	for key, old := range a {
+		if val, ok := b[key]; !ok || !reflect.DeepEqual(old, val) {
+			changes = append(changes, Change{Key: key, Old: old, New: val})
+		}
+	}
+	for key, val := range b {
+		if _, ok := a[key]; !ok {
+			changes = append(changes, Change{Key: key, New: val})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
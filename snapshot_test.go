@@ -0,0 +1,102 @@
+package enviper_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type SnapshotConfig struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+	Tags    []string
+	Limits  map[string]int
+}
+
+func TestSnapshots(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+host: localhost
+port: 8080
+timeout: 5s
+tags: [a, b]
+limits:
+  read: 10
+`)
+	defer cleanup()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var before SnapshotConfig
+	a, err := e.Snapshot(&before)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, enviper.Snapshot{
+		"Host":        "localhost",
+		"Port":        8080,
+		"Timeout":     5 * time.Second,
+		"Tags.0":      "a",
+		"Tags.1":      "b",
+		"Limits.read": 10,
+	}, a)
+	assert.Empty(t, enviper.DiffSnapshots(a, a))
+
+	defer setenv(t, map[string]string{
+		"APP_PORT":         "9090",
+		"APP_TAGS":         "a",
+		"APP_LIMITS_WRITE": "5",
+	})()
+	var after SnapshotConfig
+	b, err := e.Snapshot(&after)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 9090, after.Port)
+	assert.Equal(t, []enviper.Change{
+		{Key: "Limits.write", New: 5},
+		{Key: "Port", Old: 8080, New: 9090},
+		{Key: "Tags.1", Old: "b"},
+	}, enviper.DiffSnapshots(a, b))
+}
+
+func TestSnapshotError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PORT": "not a number"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	var c SnapshotConfig
+	_, err := e.Snapshot(&c)
+	assert.NotNil(t, err)
+}
+
+func TestSnapshotIsSetFunc(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_PORT": "8080"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	var c SnapshotConfig
+	a, err := e.Snapshot(&c)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, enviper.Snapshot{"Port": 8080}, a)
+
+	e.WithIsSetFunc(func(v reflect.Value) bool {
+		return v.Kind() == reflect.String || !v.IsZero()
+	})
+	b, err := e.Snapshot(&c)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []enviper.Change{
+		{Key: "Host", New: ""},
+	}, enviper.DiffSnapshots(a, b))
+}
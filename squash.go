@@ -0,0 +1,90 @@
+package enviper
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// WithGlobalSquash makes embedded structs without names in tags squashed, as if they were tagged with `squash`,
+// so fields of `type Config struct { Server }` are set by `MYAPP_HOST` and `host` key instead of `MYAPP_SERVER_HOST`.
+// Only exported embedded structs are squashed, embedded pointers keep their names like with the tag.
+func (e *Enviper) WithGlobalSquash() *Enviper {
+	e.globalSquash = true
+	return e
+}
+
+// isSquashed reports whether the struct field is squashed either by the tag or by WithGlobalSquash
+func (e *Enviper) isSquashed(sf reflect.StructField, name string, opts tagOptions) bool {
+	return opts.has("squash") || e.isGloballySquashed(sf, name, opts)
+}
+
+// isGloballySquashed reports whether the struct field is squashed by WithGlobalSquash only
+func (e *Enviper) isGloballySquashed(sf reflect.StructField, name string, opts tagOptions) bool {
+	return e.globalSquash && sf.Anonymous && sf.PkgPath == "" && name == "" && !opts.has("squash") &&
+		sf.Type.Kind() == reflect.Struct && !e.isLeaf(sf.Type)
+}
+
+// squashOption adds squashHook to the decode hook viper uses by default
+func (e *Enviper) squashOption() viper.DecoderConfigOption {
+	return func(c *mapstructure.DecoderConfig) {
+		hooks := []mapstructure.DecodeHookFunc{e.squashHook}
+		if c.DecodeHook != nil {
+			hooks = append(hooks, c.DecodeHook)
+		}
+		c.DecodeHook = mapstructure.ComposeDecodeHookFunc(hooks...)
+	}
+}
+
+// squashHook nests keys of globally squashed fields under their names,
+// as mapstructure squashes only the fields tagged with `squash`
+func (e *Enviper) squashHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	m, ok := data.(map[string]interface{})
+	if !ok || t.Kind() != reflect.Struct {
+		return data, nil
+	}
+	var squashed []reflect.StructField
+	own := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		switch {
+		case e.isGloballySquashed(sf, name, opts):
+			squashed = append(squashed, sf)
+		case opts.has("squash"):
+			for k := range e.structKeys(sf.Type) {
+				own[k] = true
+			}
+		case name == "":
+			own[strings.ToLower(sf.Name)] = true
+		default:
+			own[strings.ToLower(name)] = true
+		}
+	}
+	if len(squashed) == 0 {
+		return data, nil
+	}
+
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	for _, sf := range squashed {
+		keys := e.structKeys(sf.Type)
+		nested := map[string]interface{}{}
+		for k, v := range m {
+			if _, ok := keys[strings.ToLower(k)]; !ok {
+				continue
+			}
+			nested[k] = v
+			// keys of both the struct and the embedded one are decoded to both, like mapstructure does
+			if !own[strings.ToLower(k)] {
+				delete(out, k)
+			}
+		}
+		out[sf.Name] = nested
+	}
+	return out, nil
+}
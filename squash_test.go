@@ -0,0 +1,78 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type SquashLimits struct {
+	Limits map[string]int
+}
+
+type SquashBase struct {
+	SquashLimits
+	Host string
+	Port int
+}
+
+type SquashConfig struct {
+	SquashBase
+	*SquashTLS
+	Name string
+}
+
+type SquashTLS struct {
+	Cert string
+}
+
+func TestGlobalSquash(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+host: localhost
+port: 8080
+name: app
+limits:
+  read: 10
+squashtls:
+  cert: /etc/cert
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_PORT":           "9090",
+		"APP_LIMITS_WRITE":   "5",
+		"APP_SQUASHTLS_CERT": "/etc/env.crt",
+	})()
+
+	e := enviper.New(viper.New()).WithGlobalSquash()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c SquashConfig
+	if assert.Nil(t, e.UnmarshalExact(&c)) {
+		assert.Equal(t, "localhost", c.Host)
+		assert.Equal(t, 9090, c.Port)
+		assert.Equal(t, "app", c.Name)
+		assert.Equal(t, map[string]int{"read": 10, "write": 5}, c.Limits)
+		if assert.NotNil(t, c.SquashTLS) {
+			assert.Equal(t, "/etc/env.crt", c.Cert)
+		}
+	}
+}
+
+func TestGlobalSquashDisabled(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_PORT":            "9090",
+		"APP_SQUASHBASE_HOST": "localhost",
+	})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	var c SquashConfig
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "localhost", c.Host)
+	assert.Equal(t, 0, c.Port)
+}
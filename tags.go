@@ -0,0 +1,135 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagOptions are the parts of the tag after the name, e.g. `squash` in `mapstructure:",squash"`
+type tagOptions []string
+
+func (o tagOptions) has(opt string) bool {
+	for _, o := range o {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the value of the option like `anyof=auth` by its name
+func (o tagOptions) value(name string) string {
+	for _, o := range o {
+		if strings.HasPrefix(o, name+"=") {
+			return o[len(name)+1:]
+		}
+	}
+	return ""
+}
+
+// parseTag splits the tag to the name and the options
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], tagOptions(parts[1:])
+}
+
+// WithStrictTags makes Unmarshal return an error for ambiguous tags instead of guessing what was meant.
+// For now it's the tag that has both the name and `squash` option (e.g. `mapstructure:"extra,squash"`),
+// by default such fields are squashed and the name is ignored just like mapstructure does.
+func (e *Enviper) WithStrictTags() *Enviper {
+	e.strictTags = true
+	return e
+}
+
+// checkTags returns an error for the first ambiguous tag found in the type
+func (e *Enviper) checkTags(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] || e.isLeaf(t) {
+		return nil
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name != "" && name != "-" && opts.has("squash") {
+			return fmt.Errorf("field %s.%s: tag %s:%q has both name and squash", t.Name(), sf.Name, e.TagName(), sf.Tag.Get(e.TagName()))
+		}
+		if err := e.checkTags(sf.Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envTagName is the name of the tag listing env variables of the field, e.g. `env:"DATABASE_URL,DB_URL"`.
+// Env prefix is put to the names, e.g. `env:"LISTEN_ADDRESS"` is `APP_LISTEN_ADDRESS` with `APP` prefix,
+// unless the tag has `noprefix` option, e.g. `env:"DATABASE_URL,noprefix"`, so the names are used as is.
+const envTagName = "env"
+
+// parseEnvTag returns the names of env variables listed by `env` tag
+func (e *Enviper) parseEnvTag(tag string) []string {
+	var names []string
+	prefixed := true
+	for _, name := range strings.Split(tag, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "noprefix":
+			prefixed = false
+		default:
+			names = append(names, name)
+		}
+	}
+	if prefixed && e.envPrefix != "" {
+		for i, name := range names {
+			if suffix := e.prefixSuffix(); suffix != "" {
+				names[i] = name + suffix
+			} else {
+				names[i] = e.envKey(e.envPrefix) + "_" + name
+			}
+		}
+	}
+	return names
+}
+
+// defaultValueTagName is the name of the tag with the default value of the field, e.g. `default:"8080"`.
+// The value is decoded just like env variables are, so `default:"1m30s"` or `default:"a,b"` work too.
+const defaultValueTagName = "default"
+
+// validateTagName is the name of the tag with validation rules of the field, e.g. `validate:"required"`.
+// Rules other than `required` are left to validation libraries.
+const validateTagName = "validate"
+
+// validateRequired reports whether the field is required by `validate` tag
+func validateRequired(sf reflect.StructField) bool {
+	return tagOptions(strings.Split(sf.Tag.Get(validateTagName), ",")).has("required")
+}
+
+// isRequired reports whether the field is required by `required` tag option or by `validate` tag
+func isRequired(sf reflect.StructField, opts tagOptions) bool {
+	return opts.has("required") || validateRequired(sf)
+}
+
+// requiredAt reports whether the field at the path must be set. Fields with `required` tag option or `validate:"required"` tag
+// are set when provided by config file or env variables, explicit zero values included.
+func (e *Enviper) requiredAt(sf reflect.StructField, opts tagOptions, path []string) bool {
+	return isRequired(sf, opts) && !e.isProvided(path)
+}
+
+// isProvided reports whether the settings the config is decoded from have a value at the path
+func (e *Enviper) isProvided(path []string) bool {
+	return getPath(e.provided, path) != nil
+}
+
+// firstSetEnv returns the first of env variables that is set and not empty, or the first one when none is set
+func (e *Enviper) firstSetEnv(names []string) string {
+	for _, name := range names {
+		if val, ok := e.lookupEnv(name); ok && val != "" {
+			return name
+		}
+	}
+	return names[0]
+}
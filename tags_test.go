@@ -0,0 +1,239 @@
+package enviper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type AmbiguousSquashConfig struct {
+	Name  string
+	Extra ExtraConfig `mapstructure:"extra,squash"`
+}
+
+type ExtraConfig struct {
+	Level string
+}
+
+func TestSquashWithName(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_LEVEL":       "squashed",
+		"APP_EXTRA_LEVEL": "named",
+	})()
+
+	var c AmbiguousSquashConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "squashed", c.Extra.Level)
+}
+
+func TestSquashWithNameStrict(t *testing.T) {
+	var c struct {
+		Nested []AmbiguousSquashConfig
+	}
+	e := enviper.New(viper.New()).WithStrictTags()
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `field AmbiguousSquashConfig.Extra: tag mapstructure:"extra,squash" has both name and squash`, err.Error())
+	}
+}
+
+func TestStrictTagsWithoutAmbiguity(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_LEVEL": "squashed"})()
+
+	var c struct {
+		Extra ExtraConfig `mapstructure:",squash"`
+		Skip  string      `mapstructure:"-"`
+	}
+	e := enviper.New(viper.New()).WithStrictTags()
+	e.SetEnvPrefix("APP")
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "squashed", c.Extra.Level)
+}
+
+type EnvTagConfig struct {
+	URL  string `mapstructure:"url" env:"PRIMARY_URL, SECONDARY_URL,TERTIARY_URL,noprefix"`
+	Port int    `env:"SERVICE_PORT"`
+	Host string
+}
+
+func TestEnvTag(t *testing.T) {
+	for _, tc := range []struct {
+		env      map[string]string
+		expected EnvTagConfig
+	}{
+		{
+			env:      map[string]string{"TERTIARY_URL": "tertiary", "APP_URL": "derived", "APP_HOST": "host"},
+			expected: EnvTagConfig{URL: "tertiary", Host: "host"},
+		},
+		{
+			env:      map[string]string{"PRIMARY_URL": "primary", "SECONDARY_URL": "secondary", "APP_SERVICE_PORT": "80"},
+			expected: EnvTagConfig{URL: "primary", Port: 80},
+		},
+		{
+			env:      map[string]string{"PRIMARY_URL": "", "SECONDARY_URL": "secondary"},
+			expected: EnvTagConfig{URL: "secondary"},
+		},
+	} {
+		func() {
+			defer setenv(t, tc.env)()
+
+			var c EnvTagConfig
+			e := enviper.New(viper.New())
+			e.SetEnvPrefix("APP")
+			if assert.Nil(t, e.Unmarshal(&c)) {
+				assert.Equal(t, tc.expected, c)
+				assert.Equal(t, tc.expected.URL, e.GetString("url"))
+			}
+		}()
+	}
+}
+
+func TestEnvTagCustomEnvSource(t *testing.T) {
+	var c EnvTagConfig
+	e := enviper.New(viper.New()).WithEnvSource(func() []string {
+		return []string{"SECONDARY_URL=secondary", "TERTIARY_URL=tertiary"}
+	})
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "secondary", c.URL)
+}
+
+type EnvTagPrefixConfig struct {
+	Addr  string `mapstructure:"addr" env:"LISTEN_ADDRESS,noprefix"`
+	Debug bool   `env:"DEBUG_MODE,VERBOSE"`
+}
+
+func TestEnvTagPrefixedByDefault(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_LISTEN_ADDRESS": ":8080",
+		"LISTEN_ADDRESS":     ":9090",
+	})()
+
+	var c struct {
+		Addr string `mapstructure:"addr" env:"LISTEN_ADDRESS"`
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, ":8080", c.Addr)
+		assert.Equal(t, ":8080", e.GetString("addr"))
+	}
+}
+
+func TestEnvTagPrefix(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"LISTEN_ADDRESS": ":8080",
+		"APP_ADDR":       ":9090",
+		"DEBUG_MODE":     "false",
+		"APP_VERBOSE":    "true",
+	})()
+
+	var c EnvTagPrefixConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, EnvTagPrefixConfig{Addr: ":8080", Debug: true}, c)
+	}
+	// the derived name is replaced with the ones from the tag, while prefixed ones are known
+	if problems := e.LintEnv(&c); assert.Len(t, problems, 1) {
+		assert.Equal(t, "APP_ADDR", problems[0].Env)
+	}
+
+	// the prefix of the call and the one at the end are used too
+	defer setenv(t, map[string]string{"OTHER_DEBUG_MODE": "true", "VERBOSE_SFX": "true"})()
+	c = EnvTagPrefixConfig{}
+	if assert.Nil(t, e.Unmarshal(&c, enviper.CallEnvPrefix("OTHER"))) {
+		assert.True(t, c.Debug)
+	}
+	c = EnvTagPrefixConfig{}
+	e = enviper.New(viper.New()).WithSuffixPrefix()
+	e.SetEnvPrefix("SFX")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.True(t, c.Debug)
+	}
+}
+
+type DefaultsLogging struct {
+	Level string `default:"info"`
+}
+
+type DefaultsConfig struct {
+	DefaultsLogging `mapstructure:",squash"`
+	Port            int           `mapstructure:"port" default:"8080"`
+	Timeout         time.Duration `default:"1m30s"`
+	Tags            []string      `default:"a,b"`
+	Codes           []int         `default:"[200, 204]"`
+	Name            string        `default:"app"`
+	DB              struct {
+		Host string `default:"localhost"`
+		Pool int    `default:"5"`
+	}
+	Servers map[string]struct {
+		Host string
+		Port int `default:"80"`
+	}
+}
+
+func TestDefaultTag(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+name: file
+db:
+  pool: 10
+servers:
+  web:
+    host: web.local
+  api:
+    port: 8081
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_PORT":               "9090",
+		"APP_DB_HOST":            "db.local",
+		"APP_SERVERS_WEB_PORT":   "8000",
+		"APP_SERVERS_ADMIN_HOST": "admin.local",
+	})()
+
+	var c DefaultsConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "info", c.Level)
+		assert.Equal(t, 9090, c.Port)
+		assert.Equal(t, 90*time.Second, c.Timeout)
+		assert.Equal(t, []string{"a", "b"}, c.Tags)
+		assert.Equal(t, []int{200, 204}, c.Codes)
+		assert.Equal(t, "file", c.Name)
+		assert.Equal(t, "db.local", c.DB.Host)
+		assert.Equal(t, 10, c.DB.Pool)
+		assert.Equal(t, 8000, c.Servers["web"].Port)
+		assert.Equal(t, 8081, c.Servers["api"].Port)
+		assert.Equal(t, "admin.local", c.Servers["admin"].Host)
+		assert.Equal(t, 80, c.Servers["admin"].Port)
+	}
+}
+
+func TestDefaultTagOverriddenByEnv(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_LEVEL":   "debug",
+		"APP_TAGS":    "x",
+		"APP_TIMEOUT": "5s",
+	})()
+
+	var c DefaultsConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "debug", c.Level)
+		assert.Equal(t, []string{"x"}, c.Tags)
+		assert.Equal(t, 5*time.Second, c.Timeout)
+		assert.Equal(t, 8080, c.Port)
+	}
+}
@@ -0,0 +1,109 @@
+package enviper
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// WithValueTemplates makes Unmarshal evaluate string fields containing `{{` as Go templates
+// against the unmarshaled config, e.g. `MYAPP_URL=http://{{.Host}}:{{.Port}}`.
+// Templates could reference fields set by other templates, cycles and missing fields are reported as errors.
+// Values of maps are not evaluated.
+func (e *Enviper) WithValueTemplates() *Enviper {
+	e.valueTemplates = true
+	return e
+}
+
+// templateField is a string field with the template
+type templateField struct {
+	key   string
+	value reflect.Value
+	tmpl  *template.Template
+}
+
+// applyTemplates evaluates templates of string fields until all of them are resolved
+func (e *Enviper) applyTemplates(rawVal interface{}) error {
+	var pending []templateField
+	for _, f := range e.templateFields(reflect.ValueOf(rawVal), nil) {
+		tmpl, err := template.New(f.key).Option("missingkey=error").Parse(f.value.String())
+		if err != nil {
+			return fmt.Errorf("can't parse template of %s: %s", f.key, err)
+		}
+		f.tmpl = tmpl
+		pending = append(pending, f)
+	}
+
+	data := reflect.ValueOf(rawVal).Interface()
+	for len(pending) > 0 {
+		var unresolved []templateField
+		for _, f := range pending {
+			var buf bytes.Buffer
+			if err := f.tmpl.Execute(&buf, data); err != nil {
+				return fmt.Errorf("can't evaluate template of %s: %s", f.key, err)
+			}
+			// the template references fields with templates, that are not resolved yet
+			if strings.Contains(buf.String(), "{{") {
+				unresolved = append(unresolved, f)
+				continue
+			}
+			f.value.SetString(buf.String())
+		}
+		if len(unresolved) == len(pending) {
+			keys := make([]string, len(unresolved))
+			for i, f := range unresolved {
+				keys[i] = f.key
+			}
+			sort.Strings(keys)
+			return fmt.Errorf("templates reference each other: %s", strings.Join(keys, ", "))
+		}
+		pending = unresolved
+	}
+	return nil
+}
+
+// templateFields returns settable string fields containing templates
+func (e *Enviper) templateFields(v reflect.Value, path []string) []templateField {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if e.isLeaf(v.Type()) {
+		return nil
+	}
+
+	var fields []templateField
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() && strings.Contains(v.String(), "{{") {
+			fields = append(fields, templateField{key: strings.Join(path, "."), value: v})
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			name, opts := parseTag(sf.Tag.Get(e.TagName()))
+			if name == "-" {
+				continue
+			}
+			if e.isSquashed(sf, name, opts) {
+				fields = append(fields, e.templateFields(v.Field(i), path)...)
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fields = append(fields, e.templateFields(v.Field(i), appendPath(path, name))...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fields = append(fields, e.templateFields(v.Index(i), appendPath(path, strconv.Itoa(i)))...)
+		}
+	}
+	return fields
+}
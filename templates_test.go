@@ -0,0 +1,84 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type TemplateConfig struct {
+	Host    string
+	Port    int
+	URL     string
+	Health  string
+	Servers []Server
+}
+
+func TestValueTemplates(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+host: localhost
+port: 8080
+health: "{{.URL}}/health"
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_URL":              "http://{{.Host}}:{{.Port}}",
+		"APP_SERVERS_0_HOST":   "{{.Host}}",
+		"APP_SERVERS_0_TLS_CA": "{{.Host}}.crt",
+	})()
+
+	var c TemplateConfig
+	e := enviper.New(viper.New()).WithValueTemplates()
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "http://localhost:8080", c.URL)
+	assert.Equal(t, "http://localhost:8080/health", c.Health)
+	if assert.Len(t, c.Servers, 1) {
+		assert.Equal(t, "localhost", c.Servers[0].Host)
+		assert.Equal(t, []string{"localhost.crt"}, c.Servers[0].TLS.CA)
+	}
+}
+
+func TestValueTemplatesDisabledByDefault(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_URL": "http://{{.Host}}"})()
+
+	var c TemplateConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Equal(t, "http://{{.Host}}", c.URL)
+}
+
+func TestValueTemplatesErrors(t *testing.T) {
+	for message, env := range map[string]map[string]string{
+		"templates reference each other: Health, URL": {
+			"APP_URL":    "{{.Health}}",
+			"APP_HEALTH": "{{.URL}}/health",
+		},
+		"can't evaluate template of URL": {
+			"APP_URL": "{{.Missing}}",
+		},
+		"can't parse template of URL": {
+			"APP_URL": "{{.Host",
+		},
+	} {
+		func() {
+			defer setenv(t, env)()
+
+			var c TemplateConfig
+			e := enviper.New(viper.New()).WithValueTemplates()
+			e.SetEnvPrefix("APP")
+
+			err := e.Unmarshal(&c)
+			if assert.NotNil(t, err, message) {
+				assert.Contains(t, err.Error(), message)
+			}
+		}()
+	}
+}
@@ -0,0 +1,71 @@
+package enviper
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithUnixTimestamps makes time.Time fields accept Unix timestamps in the unit, e.g. time.Second or time.Millisecond,
+// so `MYAPP_START=1704204000` is `2024-01-02T14:00:00Z`. Values that are not integers are parsed as RFC3339 times.
+// Times are in UTC.
+func (e *Enviper) WithUnixTimestamps(unit time.Duration) *Enviper {
+	e.unixTimestampUnit = unit
+	return e
+}
+
+// unixTimestampHook parses integers as Unix timestamps and leaves other values to the default time hook
+func (e *Enviper) unixTimestampHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if t != timeType {
+		return data, nil
+	}
+	var n int64
+	switch f.Kind() {
+	case reflect.String:
+		var err error
+		if n, err = strconv.ParseInt(strings.TrimSpace(reflect.ValueOf(data).String()), 10, 64); err != nil {
+			return data, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = reflect.ValueOf(data).Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = int64(reflect.ValueOf(data).Uint())
+	default:
+		return data, nil
+	}
+	unit := e.unixTimestampUnit
+	if unit >= time.Second {
+		return time.Unix(n*int64(unit/time.Second), 0).UTC(), nil
+	}
+	perSecond := int64(time.Second / unit)
+	return time.Unix(n/perSecond, n%perSecond*int64(unit)).UTC(), nil
+}
+
+// WithDefaultTimeLocation makes time.Time fields accept times without zone, that are interpreted in the location,
+// e.g. `MYAPP_START=2024-01-02T15:00:00` with `time.UTC`. Accepted layouts are RFC3339 without zone
+// with `T` or space between date and time, and dates only. Times with zone are parsed as RFC3339 as usual.
+func (e *Enviper) WithDefaultTimeLocation(loc *time.Location) *Enviper {
+	e.defaultTimeLocation = loc
+	return e
+}
+
+var zonelessLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// zonelessTimeHook parses times without zone in the default location and leaves other strings to the default time hook
+func (e *Enviper) zonelessTimeHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != timeType {
+		return data, nil
+	}
+	raw := strings.TrimSpace(reflect.ValueOf(data).String())
+	for _, layout := range zonelessLayouts {
+		if parsed, err := time.ParseInLocation(layout, raw, e.defaultTimeLocation); err == nil {
+			return parsed, nil
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,92 @@
+package enviper_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func unmarshalTime(t *testing.T, e *enviper.Enviper, raw string) (time.Time, error) {
+	defer setenv(t, map[string]string{"APP_START": raw})()
+
+	var c struct {
+		Start time.Time
+	}
+	e.SetEnvPrefix("APP")
+	err := e.Unmarshal(&c)
+	return c.Start, err
+}
+
+func TestUnixTimestamps(t *testing.T) {
+	for _, tc := range []struct {
+		unit     time.Duration
+		raw      string
+		expected time.Time
+	}{
+		{time.Second, "1704204000", time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC)},
+		{time.Second, " 0 ", time.Unix(0, 0).UTC()},
+		{time.Second, "-60", time.Date(1969, 12, 31, 23, 59, 0, 0, time.UTC)},
+		{time.Millisecond, "1704204000123", time.Date(2024, 1, 2, 14, 0, 0, 123000000, time.UTC)},
+		{time.Millisecond, "-1", time.Date(1969, 12, 31, 23, 59, 59, 999000000, time.UTC)},
+		{time.Second, "2024-01-02T15:00:00+01:00", time.Date(2024, 1, 2, 15, 0, 0, 0, time.FixedZone("", 3600))},
+		{time.Millisecond, "2024-01-02T14:00:00Z", time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC)},
+	} {
+		start, err := unmarshalTime(t, enviper.New(viper.New()).WithUnixTimestamps(tc.unit), tc.raw)
+		if assert.Nil(t, err, tc.raw) {
+			assert.True(t, tc.expected.Equal(start), "%s: %s != %s", tc.raw, tc.expected, start)
+		}
+	}
+}
+
+func TestUnixTimestampsFromFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, "start: 1704204000")
+	defer cleanup()
+
+	var c struct {
+		Start time.Time
+	}
+	e := enviper.New(viper.New()).WithUnixTimestamps(time.Second)
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC), c.Start)
+	}
+}
+
+func TestUnixTimestampsDisabled(t *testing.T) {
+	_, err := unmarshalTime(t, enviper.New(viper.New()), "1704204000")
+	assert.NotNil(t, err)
+}
+
+func TestDefaultTimeLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, tc := range []struct {
+		loc      *time.Location
+		raw      string
+		expected time.Time
+	}{
+		{time.UTC, "2024-01-02T15:00:00", time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)},
+		{time.UTC, "2024-01-02 15:00:00.5", time.Date(2024, 1, 2, 15, 0, 0, 500000000, time.UTC)},
+		{berlin, "2024-07-02T15:00:00", time.Date(2024, 7, 2, 13, 0, 0, 0, time.UTC)},
+		{berlin, "2024-01-02", time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)},
+		{berlin, "2024-01-02T15:00:00Z", time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)},
+	} {
+		start, err := unmarshalTime(t, enviper.New(viper.New()).WithDefaultTimeLocation(tc.loc), tc.raw)
+		if assert.Nil(t, err, tc.raw) {
+			assert.True(t, tc.expected.Equal(start), "%s: %s != %s", tc.raw, tc.expected, start)
+			if !strings.HasSuffix(tc.raw, "Z") {
+				assert.Equal(t, tc.loc, start.Location(), tc.raw)
+			}
+		}
+	}
+
+	_, err = unmarshalTime(t, enviper.New(viper.New()), "2024-01-02T15:00:00")
+	assert.NotNil(t, err)
+}
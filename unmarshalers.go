@@ -0,0 +1,67 @@
+package enviper
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isJSONUnmarshaler reports whether fields of the type are decoded with json.Unmarshaler,
+// time.Time is left to its own hook
+func isJSONUnmarshaler(t reflect.Type) bool {
+	return t != timeType && t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface &&
+		reflect.PtrTo(t).Implements(jsonUnmarshalerType)
+}
+
+// jsonUnmarshalerHook decodes values of types implementing json.Unmarshaler.
+// Strings are passed to UnmarshalText when the type implements encoding.TextUnmarshaler as well,
+// otherwise to UnmarshalJSON as is when they are valid JSON, or quoted when they are not (e.g. `debug`).
+// Other values, e.g. maps from config file, are encoded to JSON first.
+func jsonUnmarshalerHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f == t || !isJSONUnmarshaler(t) {
+		return data, nil
+	}
+	out := reflect.New(t)
+	if f.Kind() != reflect.String {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return data, nil
+		}
+		err = out.Interface().(json.Unmarshaler).UnmarshalJSON(raw)
+		return out.Elem().Interface(), err
+	}
+
+	s := reflect.ValueOf(data).String()
+	if u, ok := out.Interface().(encoding.TextUnmarshaler); ok {
+		err := u.UnmarshalText([]byte(s))
+		return out.Elem().Interface(), err
+	}
+	raw := []byte(s)
+	if !json.Valid(raw) {
+		raw, _ = json.Marshal(s)
+	}
+	err := out.Interface().(json.Unmarshaler).UnmarshalJSON(raw)
+	return out.Elem().Interface(), err
+}
+
+// isTextUnmarshaler reports whether fields of the type are decoded with encoding.TextUnmarshaler only
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t != timeType && t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface &&
+		reflect.PtrTo(t).Implements(textUnmarshalerType) && !isJSONUnmarshaler(t)
+}
+
+// textUnmarshalerHook decodes strings to values of types implementing encoding.TextUnmarshaler, but not json.Unmarshaler,
+// e.g. net.IP. Types implementing both are left to jsonUnmarshalerHook.
+func textUnmarshalerHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || f == t || !isTextUnmarshaler(t) {
+		return data, nil
+	}
+	out := reflect.New(t)
+	err := out.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(reflect.ValueOf(data).String()))
+	return out.Elem().Interface(), err
+}
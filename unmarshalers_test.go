@@ -0,0 +1,126 @@
+package enviper_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// jsonLevel implements json.Unmarshaler only
+type jsonLevel struct {
+	name string
+}
+
+func (l *jsonLevel) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	if name != "debug" && name != "info" {
+		return fmt.Errorf("unknown level %q", name)
+	}
+	l.name = name
+	return nil
+}
+
+// jsonPair implements json.Unmarshaler only and is encoded as JSON object
+type jsonPair struct {
+	key, value string
+}
+
+func (p *jsonPair) UnmarshalJSON(b []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	p.key, p.value = m["key"], m["value"]
+	return nil
+}
+
+// bothUnmarshalers implements both json.Unmarshaler and encoding.TextUnmarshaler
+type bothUnmarshalers string
+
+func (b *bothUnmarshalers) UnmarshalJSON([]byte) error {
+	*b = "json"
+	return nil
+}
+
+func (b *bothUnmarshalers) UnmarshalText(text []byte) error {
+	*b = bothUnmarshalers("text:" + strings.ToUpper(string(text)))
+	return nil
+}
+
+type JSONUnmarshalerConfig struct {
+	Level    jsonLevel
+	Optional *jsonLevel
+	Pair     jsonPair
+	Both     bothUnmarshalers
+	Levels   []jsonLevel
+}
+
+func TestJSONUnmarshalerFromEnv(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_LEVEL":    "debug",
+		"APP_OPTIONAL": `"info"`,
+		"APP_PAIR":     `{"key":"a","value":"b"}`,
+		"APP_BOTH":     "x",
+		"APP_LEVELS_1": "info",
+	})()
+	dir, cleanup := writeConfig(t, "levels: [debug, debug]")
+	defer cleanup()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c JSONUnmarshalerConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, jsonLevel{"debug"}, c.Level)
+		if assert.NotNil(t, c.Optional) {
+			assert.Equal(t, jsonLevel{"info"}, *c.Optional)
+		}
+		assert.Equal(t, jsonPair{"a", "b"}, c.Pair)
+		assert.Equal(t, bothUnmarshalers("text:X"), c.Both)
+		assert.Equal(t, []jsonLevel{{"debug"}, {"info"}}, c.Levels)
+	}
+}
+
+func TestJSONUnmarshalerFromFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+level: info
+pair:
+  key: a
+  value: b
+`)
+	defer cleanup()
+
+	e := enviper.New(viper.New())
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c JSONUnmarshalerConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, jsonLevel{"info"}, c.Level)
+		assert.Nil(t, c.Optional)
+		assert.Equal(t, jsonPair{"a", "b"}, c.Pair)
+	}
+}
+
+func TestJSONUnmarshalerError(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_LEVEL": "verbose"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	var c JSONUnmarshalerConfig
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `unknown level "verbose"`)
+	}
+}
@@ -0,0 +1,264 @@
+package enviper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithRequireAllFields makes Unmarshal return an error listing every field left with zero value,
+// so entirely unconfigured parts of the config are caught.
+// Fields tagged with `omitempty` or `-` and nil pointers are treated as optional.
+func (e *Enviper) WithRequireAllFields() *Enviper {
+	e.requireAllFields = true
+	return e
+}
+
+// WithIsSetFunc sets the func deciding whether the value of a field is set,
+// that is used by WithRequireAllFields, `required`, `anyof` and `oneof` tags, snapshots and DefaultsLayer.
+// By default values are set unless they are zero values, e.g. the func treating empty strings as set:
+//
+//	e.WithIsSetFunc(func(v reflect.Value) bool {
+//		return v.Kind() == reflect.String || !v.IsZero()
+//	})
+//
+// Nil pointers to structs are optional anyway, so their fields are not checked.
+func (e *Enviper) WithIsSetFunc(isSet func(reflect.Value) bool) *Enviper {
+	e.isSetFunc = isSet
+	return e
+}
+
+// isValueSet reports whether the value is set
+func (e *Enviper) isValueSet(v reflect.Value) bool {
+	if e.isSetFunc != nil {
+		return e.isSetFunc(v)
+	}
+	return !v.IsZero()
+}
+
+// MissingField is a field with `validate:"required"` tag that is set neither by config file nor by env variables
+type MissingField struct {
+	// Key is the path of the field, e.g. `db.host`
+	Key string
+	// Env is the name of env variable that would set the field, e.g. `MYAPP_DB_HOST`
+	Env string
+}
+
+// MissingFieldsError is returned by Unmarshal when fields with `validate:"required"` tag are not set,
+// sorted by their keys
+type MissingFieldsError []MissingField
+
+func (m MissingFieldsError) Error() string {
+	described := make([]string, len(m))
+	for i, f := range m {
+		described[i] = f.Key
+		if f.Env != "" {
+			described[i] += " (" + f.Env + ")"
+		}
+	}
+	return "required fields are not set: " + strings.Join(described, ", ")
+}
+
+// validate checks the config after it's unmarshaled
+func (e *Enviper) validate(rawVal interface{}) error {
+	if missing := e.missingRequired(reflect.ValueOf(rawVal), nil, func(sf reflect.StructField, _ tagOptions, path []string) bool {
+		return validateRequired(sf) && !e.isProvided(path)
+	}); len(missing) > 0 {
+		fields := e.missingFields(rawVal, missing)
+		if e.failFast {
+			fields = fields[:1]
+		}
+		return fields
+	}
+	if e.requireAllFields {
+		if zero := e.zeroFields(reflect.ValueOf(rawVal), nil); len(zero) > 0 {
+			return fmt.Errorf("fields are not set: %s", strings.Join(e.firstOnly(zero), ", "))
+		}
+	}
+	if unset := e.unsetGroups(reflect.ValueOf(rawVal), nil); len(unset) > 0 {
+		return fmt.Errorf("none of fields of groups are set: %s", strings.Join(e.firstOnly(unset), "; "))
+	}
+	if disallowed := e.disallowedValues(reflect.ValueOf(rawVal), nil, nil); len(disallowed) > 0 {
+		return fmt.Errorf("values are not allowed: %s", strings.Join(e.firstOnly(disallowed), "; "))
+	}
+	return nil
+}
+
+// missingFields sorts paths of fields of rawVal and adds the names of env variables setting them
+func (e *Enviper) missingFields(rawVal interface{}, paths []string) MissingFieldsError {
+	envs := map[string]string{}
+	e.walkElements(field{value: reflect.ValueOf(rawVal)}, func(f field) {
+		envs[strings.Join(f.path, ".")] = e.fieldEnvName(f)
+	}, allElements)
+	sort.Strings(paths)
+	fields := make(MissingFieldsError, len(paths))
+	for i, path := range paths {
+		fields[i] = MissingField{Key: path, Env: envs[path]}
+	}
+	return fields
+}
+
+// disallowedValues returns descriptions of values of fields that are not in the set of their `oneof` tag,
+// e.g. `oneof:"debug info warn error"`. Zero values are allowed, elements of slices are checked one by one.
+func (e *Enviper) disallowedValues(v reflect.Value, path []string, allowed []string) []string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var disallowed []string
+	switch {
+	case v.Kind() == reflect.Struct && !e.isLeaf(v.Type()):
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			name, opts := parseTag(sf.Tag.Get(e.TagName()))
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			if !e.isSquashed(sf, name, opts) {
+				if name == "" {
+					name = sf.Name
+				}
+				fieldPath = appendPath(path, name)
+			}
+			disallowed = append(disallowed, e.disallowedValues(v.Field(i), fieldPath, strings.Fields(sf.Tag.Get("oneof")))...)
+		}
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && !e.isLeaf(v.Type()):
+		for i := 0; i < v.Len(); i++ {
+			disallowed = append(disallowed, e.disallowedValues(v.Index(i), appendPath(path, strconv.Itoa(i)), allowed)...)
+		}
+	case v.Kind() == reflect.Map && !e.isLeaf(v.Type()):
+		iter := v.MapRange()
+		for iter.Next() {
+			disallowed = append(disallowed, e.disallowedValues(iter.Value(), appendPath(path, fmt.Sprint(valueInterface(iter.Key()))), nil)...)
+		}
+	case len(allowed) > 0 && e.isValueSet(v):
+		val := fmt.Sprint(valueInterface(v))
+		for _, a := range allowed {
+			if val == a {
+				return nil
+			}
+		}
+		disallowed = append(disallowed, fmt.Sprintf("%s is %q, allowed: %s", strings.Join(path, "."), val, strings.Join(allowed, ", ")))
+	}
+	return disallowed
+}
+
+// unsetGroups returns descriptions of `anyof` groups that have none of their fields set,
+// e.g. `mapstructure:"token,anyof=auth"`. Groups are scoped by the struct, squashed fields are in the same scope.
+func (e *Enviper) unsetGroups(v reflect.Value, path []string) []string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if e.isLeaf(v.Type()) {
+		return nil
+	}
+
+	var unset []string
+	switch v.Kind() {
+	case reflect.Struct:
+		groups := map[string][]string{}
+		set := map[string]bool{}
+		unset = e.collectGroups(v, path, groups, set)
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !set[name] {
+				unset = append(unset, fmt.Sprintf("%s (%s)", name, strings.Join(groups[name], ", ")))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			unset = append(unset, e.unsetGroups(v.Index(i), appendPath(path, strconv.Itoa(i)))...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			unset = append(unset, e.unsetGroups(iter.Value(), appendPath(path, fmt.Sprint(valueInterface(iter.Key()))))...)
+		}
+	}
+	return unset
+}
+
+// collectGroups collects paths of fields of the struct by their groups and whether groups are set,
+// it returns unset groups of nested values
+func (e *Enviper) collectGroups(v reflect.Value, path []string, groups map[string][]string, set map[string]bool) []string {
+	var unset []string
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if e.isSquashed(sf, name, opts) {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				unset = append(unset, e.collectGroups(fv, path, groups, set)...)
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fieldPath := appendPath(path, name)
+		if group := opts.value("anyof"); group != "" {
+			groups[group] = append(groups[group], strings.Join(fieldPath, "."))
+			if e.isValueSet(fv) {
+				set[group] = true
+			}
+		}
+		unset = append(unset, e.unsetGroups(fv, fieldPath)...)
+	}
+	return unset
+}
+
+// zeroFields returns paths of fields that have zero values
+func (e *Enviper) zeroFields(v reflect.Value, path []string) []string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || e.isLeaf(v.Type()) {
+		if !e.isValueSet(v) {
+			return []string{strings.Join(path, ".")}
+		}
+		return nil
+	}
+
+	var zero []string
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if sf.PkgPath != "" && !e.setterBinding {
+			continue
+		}
+		name, opts := parseTag(sf.Tag.Get(e.TagName()))
+		if name == "-" || opts.has("omitempty") {
+			continue
+		}
+		if e.isSquashed(sf, name, opts) {
+			zero = append(zero, e.zeroFields(v.Field(i), path)...)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		zero = append(zero, e.zeroFields(v.Field(i), appendPath(path, name))...)
+	}
+	return zero
+}
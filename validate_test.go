@@ -0,0 +1,266 @@
+package enviper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type RequireAllConfig struct {
+	Name     string
+	Port     int `mapstructure:"port"`
+	Tags     []string
+	Optional string `mapstructure:",omitempty"`
+	Skipped  string `mapstructure:"-"`
+	Pointer  *PtrTest
+	Embedded `mapstructure:",squash"`
+	DB       struct {
+		Host string
+		User string
+	}
+}
+
+type Embedded struct {
+	Level string
+}
+
+func TestRequireAllFields(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_NAME":    "app",
+		"APP_PORT":    "8080",
+		"APP_TAGS":    "a,b",
+		"APP_LEVEL":   "debug",
+		"APP_DB_HOST": "localhost",
+		"APP_DB_USER": "admin",
+	})()
+
+	var c RequireAllConfig
+	e := enviper.New(viper.New()).WithRequireAllFields()
+	e.SetEnvPrefix("APP")
+
+	assert.Nil(t, e.Unmarshal(&c))
+	assert.Nil(t, c.Pointer)
+}
+
+func TestRequireAllFieldsMissing(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_NAME":    "app",
+		"APP_DB_HOST": "localhost",
+	})()
+
+	c := RequireAllConfig{Pointer: &PtrTest{}}
+	e := enviper.New(viper.New()).WithRequireAllFields()
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "fields are not set: port, Tags, Pointer.Value, Level, DB.User", err.Error())
+	}
+}
+
+type AnyOfConfig struct {
+	Auth struct {
+		Token    string `mapstructure:",anyof=credentials"`
+		Password string `mapstructure:"pass,anyof=credentials"`
+		CertFile string `mapstructure:",anyof=credentials"`
+	}
+	Upstreams []struct {
+		Host string `mapstructure:",anyof=address"`
+		IP   string `mapstructure:",anyof=address"`
+	}
+}
+
+func TestAnyOfGroups(t *testing.T) {
+	for _, env := range []map[string]string{
+		{"APP_AUTH_TOKEN": "secret"},
+		{"APP_AUTH_TOKEN": "secret", "APP_AUTH_PASS": "password"},
+		{"APP_AUTH_CERTFILE": "/etc/cert.pem", "APP_UPSTREAMS_0_IP": "10.0.0.1"},
+	} {
+		func() {
+			defer setenv(t, env)()
+
+			var c AnyOfConfig
+			e := enviper.New(viper.New())
+			e.SetEnvPrefix("APP")
+			assert.Nil(t, e.Unmarshal(&c), env)
+		}()
+	}
+}
+
+func TestAnyOfGroupsNoneSet(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_UPSTREAMS_1_HOST": "localhost"})()
+
+	var c AnyOfConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "none of fields of groups are set: "+
+			"credentials (Auth.Token, Auth.pass, Auth.CertFile); "+
+			"address (Upstreams.0.Host, Upstreams.0.IP)", err.Error())
+	}
+}
+
+type OneOfConfig struct {
+	Level  string  `mapstructure:"level" oneof:"debug info warn error"`
+	Mode   *string `oneof:"fast safe"`
+	Codes  []int   `oneof:"200 204"`
+	Format string
+}
+
+func TestOneOfAllowed(t *testing.T) {
+	for _, env := range []map[string]string{
+		{},
+		{"APP_LEVEL": "debug", "APP_MODE": "safe", "APP_CODES": "200,204"},
+		{"APP_LEVEL": "error", "APP_FORMAT": "anything"},
+	} {
+		func() {
+			defer setenv(t, env)()
+
+			var c OneOfConfig
+			e := enviper.New(viper.New())
+			e.SetEnvPrefix("APP")
+			assert.Nil(t, e.Unmarshal(&c), env)
+		}()
+	}
+}
+
+func TestOneOfDisallowed(t *testing.T) {
+	dir, cleanup := writeConfig(t, "level: verbose")
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_MODE":  "slow",
+		"APP_CODES": "200,500",
+	})()
+
+	var c OneOfConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `values are not allowed: `+
+			`level is "verbose", allowed: debug, info, warn, error; `+
+			`Mode is "slow", allowed: fast, safe; `+
+			`Codes.1 is "500", allowed: 200, 204`, err.Error())
+	}
+}
+
+func TestIsSetFunc(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_TAGS": "a",
+	})()
+
+	emptyStringsAreSet := func(v reflect.Value) bool {
+		return v.Kind() == reflect.String || !v.IsZero()
+	}
+
+	var c RequireAllConfig
+	e := enviper.New(viper.New()).WithRequireAllFields().WithIsSetFunc(emptyStringsAreSet)
+	e.SetEnvPrefix("APP")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "fields are not set: port", err.Error())
+	}
+}
+
+type ValidateRequiredConfig struct {
+	Name       string
+	DBPassword string `mapstructure:"db_password" validate:"required"`
+	DB         struct {
+		Host string `validate:"required,hostname"`
+		Port int
+	}
+	Servers []struct {
+		Host string `mapstructure:"host" validate:"required"`
+	}
+	Token string `env:"API_TOKEN,TOKEN,noprefix" validate:"required"`
+}
+
+func TestValidateRequired(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+db:
+  host: localhost
+servers:
+  - host: a
+  - port: 1
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_DB_PORT": "5432"})()
+
+	var c ValidateRequiredConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "required fields are not set: Servers.1.host (APP_SERVERS_1_HOST), Token (API_TOKEN), db_password (APP_DB_PASSWORD)", err.Error())
+		assert.Equal(t, enviper.MissingFieldsError{
+			{Key: "Servers.1.host", Env: "APP_SERVERS_1_HOST"},
+			{Key: "Token", Env: "API_TOKEN"},
+			{Key: "db_password", Env: "APP_DB_PASSWORD"},
+		}, err)
+	}
+
+	err = e.WithFailFast().Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "required fields are not set: Servers.1.host (APP_SERVERS_1_HOST)", err.Error())
+	}
+}
+
+func TestValidateRequiredSet(t *testing.T) {
+	defer setenv(t, map[string]string{
+		"APP_DB_PASSWORD": "secret",
+		"APP_DB_HOST":     "localhost",
+		"TOKEN":           "t",
+	})()
+
+	var c ValidateRequiredConfig
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, "secret", c.DBPassword)
+		assert.Equal(t, "t", c.Token)
+	}
+}
+
+func TestValidateRequiredExplicitZero(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+debug: false
+port: 0
+name: ""
+`)
+	defer cleanup()
+
+	var c struct {
+		Debug bool   `validate:"required"`
+		Port  int    `validate:"required"`
+		Name  string `validate:"required"`
+		Host  string `validate:"required"`
+	}
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		missing, ok := err.(enviper.MissingFieldsError)
+		if assert.True(t, ok) {
+			assert.Equal(t, enviper.MissingFieldsError{{Key: "Host", Env: "APP_HOST"}}, missing)
+		}
+	}
+
+	defer setenv(t, map[string]string{"APP_HOST": "localhost"})()
+	assert.Nil(t, e.Unmarshal(&c))
+}
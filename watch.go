@@ -0,0 +1,104 @@
+package enviper
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// defaultEnvWatchInterval is the interval of scanning env variables for changes by WatchUnmarshal
+const defaultEnvWatchInterval = 5 * time.Second
+
+// WithEnvWatchInterval sets the interval of scanning env variables for changes by WatchUnmarshal, 5 seconds by default.
+// Env variables of the process rarely change, but the ones of the source set with WithEnvSource could.
+func (e *Enviper) WithEnvWatchInterval(d time.Duration) *Enviper {
+	e.envWatchInterval = d
+	return e
+}
+
+// WatchUnmarshal unmarshals the config to rawVal, that must be a pointer, and unmarshals it again
+// whenever config file changes (see viper.WatchConfig) or env variables change.
+// Every time the config is unmarshaled to a fresh value, that replaces the value rawVal points to
+// before onChange is called with nil, or onChange is called with the error leaving rawVal intact.
+// onChange is called from other goroutines, so reading rawVal must be synchronized with it.
+// The stop func stops reloading, though viper keeps watching config file as it can't be stopped.
+func (e *Enviper) WatchUnmarshal(rawVal interface{}, onChange func(error), opts ...viper.DecoderConfigOption) (func(), error) {
+	rv := reflect.ValueOf(rawVal)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, errors.New("can't watch config, rawVal must be a non-nil pointer")
+	}
+	// env is taken before unmarshaling, so changes made meanwhile are not missed
+	env := e.environSnapshot()
+	if err := e.Unmarshal(rawVal, opts...); err != nil {
+		return nil, err
+	}
+
+	// reloads are serialized, as Unmarshal isn't safe to call concurrently
+	var mu sync.Mutex
+	done := make(chan struct{})
+	reload := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case <-done:
+			return
+		default:
+		}
+		fresh := reflect.New(rv.Type().Elem())
+		if err := e.Unmarshal(fresh.Interface(), opts...); err != nil {
+			onChange(err)
+			return
+		}
+		rv.Elem().Set(fresh.Elem())
+		onChange(nil)
+	}
+
+	if e.Viper.ConfigFileUsed() != "" {
+		e.Viper.OnConfigChange(func(fsnotify.Event) {
+			reload()
+		})
+		e.Viper.WatchConfig()
+	}
+
+	interval := e.envWatchInterval
+	if interval <= 0 {
+		interval = defaultEnvWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if current := e.environSnapshot(); current != env {
+					env = current
+					reload()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}, nil
+}
+
+// environSnapshot returns env variables sorted and joined, so changes of them could be detected
+func (e *Enviper) environSnapshot() string {
+	env := e.environ()
+	sorted := make([]string, len(env))
+	copy(sorted, env)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
@@ -0,0 +1,123 @@
+package enviper_test
+
+import (
+	"io/ioutil"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type WatchConfig struct {
+	Host string
+	Port int
+}
+
+// mutableEnv is the env source that could be changed while watching
+type mutableEnv struct {
+	mu  sync.Mutex
+	env []string
+}
+
+func (m *mutableEnv) set(env ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.env = env
+}
+
+func (m *mutableEnv) environ() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.env...)
+}
+
+func waitChange(t *testing.T, changes chan error) error {
+	select {
+	case err := <-changes:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("config is not reloaded")
+		return nil
+	}
+}
+
+func TestWatchUnmarshalEnv(t *testing.T) {
+	env := &mutableEnv{}
+	env.set("APP_HOST=a", "APP_PORT=80")
+	e := enviper.New(viper.New()).WithEnvSource(env.environ).WithEnvWatchInterval(10 * time.Millisecond)
+	e.SetEnvPrefix("APP")
+
+	var c WatchConfig
+	changes := make(chan error, 10)
+	stop, err := e.WatchUnmarshal(&c, func(err error) { changes <- err })
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer stop()
+	assert.Equal(t, WatchConfig{Host: "a", Port: 80}, c)
+
+	env.set("APP_HOST=b", "APP_PORT=80")
+	if assert.Nil(t, waitChange(t, changes)) {
+		assert.Equal(t, WatchConfig{Host: "b", Port: 80}, c)
+	}
+
+	// invalid values leave the config intact
+	env.set("APP_HOST=c", "APP_PORT=eighty")
+	if assert.NotNil(t, waitChange(t, changes)) {
+		assert.Equal(t, WatchConfig{Host: "b", Port: 80}, c)
+	}
+
+	// fields missing in the new config are reset
+	env.set("APP_PORT=81")
+	if assert.Nil(t, waitChange(t, changes)) {
+		assert.Equal(t, WatchConfig{Port: 81}, c)
+	}
+
+	stop()
+	env.set("APP_PORT=82")
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, changes, 0)
+	assert.Equal(t, WatchConfig{Port: 81}, c)
+}
+
+func TestWatchUnmarshalFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, "host: a\nport: 80\n")
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_PORT": "8080"})()
+
+	e := enviper.New(viper.New())
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c WatchConfig
+	changes := make(chan error, 10)
+	stop, err := e.WatchUnmarshal(&c, func(err error) { changes <- err })
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer stop()
+	assert.Equal(t, WatchConfig{Host: "a", Port: 8080}, c)
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(dir, "config.yaml"), []byte("host: b\nport: 81\n"), 0600))
+	if assert.Nil(t, waitChange(t, changes)) {
+		assert.Equal(t, WatchConfig{Host: "b", Port: 8080}, c)
+	}
+}
+
+func TestWatchUnmarshalErrors(t *testing.T) {
+	e := enviper.New(viper.New())
+	var c WatchConfig
+
+	_, err := e.WatchUnmarshal(c, func(error) {})
+	assert.EqualError(t, err, "can't watch config, rawVal must be a non-nil pointer")
+
+	defer setenv(t, map[string]string{"APP_PORT": "eighty"})()
+	e.SetEnvPrefix("APP")
+	_, err = e.WatchUnmarshal(&c, func(error) {})
+	assert.NotNil(t, err)
+}
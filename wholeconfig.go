@@ -0,0 +1,43 @@
+package enviper
+
+import (
+	"fmt"
+)
+
+// WithWholeConfigEnv makes Unmarshal read the whole config as JSON document from the env variable,
+// e.g. `MYAPP_CONFIG_JSON={"db":{"host":"localhost"}}`.
+// The document is a layer above config file and below env variables of fields,
+// use WithWholeConfigEnvBelowFile to put it below config file.
+func (e *Enviper) WithWholeConfigEnv(name string) *Enviper {
+	e.wholeConfigEnv = name
+	return e
+}
+
+// WithWholeConfigEnvBelowFile puts the document from WithWholeConfigEnv below config file,
+// so it acts as defaults for both config file and env variables of fields.
+func (e *Enviper) WithWholeConfigEnvBelowFile() *Enviper {
+	e.wholeConfigBelow = true
+	return e
+}
+
+// readWholeConfigEnv adds the document from the whole config env variable to viper
+func (e *Enviper) readWholeConfigEnv() error {
+	if e.wholeConfigEnv == "" {
+		return nil
+	}
+	raw, ok := e.lookupEnv(e.wholeConfigEnv)
+	if !ok || raw == "" {
+		return nil
+	}
+	var doc map[string]interface{}
+	if err := unmarshalJSON(raw, &doc, e.jsonNumberMode); err != nil {
+		return fmt.Errorf("can't parse %s as JSON object: %s", e.wholeConfigEnv, err)
+	}
+	if e.wholeConfigBelow {
+		for key, val := range doc {
+			e.Viper.SetDefault(key, val)
+		}
+		return nil
+	}
+	return e.Viper.MergeConfigMap(doc)
+}
@@ -0,0 +1,73 @@
+package enviper_test
+
+import (
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type WholeConfig struct {
+	Name string
+	DB   struct {
+		Host string
+		Port int
+		User string
+	}
+	Tags []string
+}
+
+func unmarshalWholeConfig(t *testing.T, e *enviper.Enviper) WholeConfig {
+	dir, cleanup := writeConfig(t, `
+name: file
+db:
+  host: file.host
+  port: 1
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_CONFIG_JSON": `{"name":"json","db":{"host":"json.host","port":2,"user":"json"},"tags":["a","b"]}`,
+		"APP_DB_PORT":     "3",
+	})()
+
+	var c WholeConfig
+	e.SetEnvPrefix("APP")
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+	assert.Nil(t, e.Unmarshal(&c))
+	return c
+}
+
+func TestWholeConfigEnv(t *testing.T) {
+	c := unmarshalWholeConfig(t, enviper.New(viper.New()).WithWholeConfigEnv("APP_CONFIG_JSON"))
+
+	assert.Equal(t, "json", c.Name)
+	assert.Equal(t, "json.host", c.DB.Host)
+	assert.Equal(t, 3, c.DB.Port)
+	assert.Equal(t, "json", c.DB.User)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+}
+
+func TestWholeConfigEnvBelowFile(t *testing.T) {
+	c := unmarshalWholeConfig(t, enviper.New(viper.New()).
+		WithWholeConfigEnv("APP_CONFIG_JSON").
+		WithWholeConfigEnvBelowFile())
+
+	assert.Equal(t, "file", c.Name)
+	assert.Equal(t, "file.host", c.DB.Host)
+	assert.Equal(t, 3, c.DB.Port)
+	assert.Equal(t, "json", c.DB.User)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+}
+
+func TestWholeConfigEnvInvalid(t *testing.T) {
+	defer setenv(t, map[string]string{"APP_CONFIG_JSON": `{"name":`})()
+
+	var c WholeConfig
+	e := enviper.New(viper.New()).WithWholeConfigEnv("APP_CONFIG_JSON")
+	err := e.Unmarshal(&c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't parse APP_CONFIG_JSON as JSON object")
+	}
+}
@@ -0,0 +1,40 @@
+package enviper
+
+import (
+	"reflect"
+)
+
+// wrapper describes fields of the registered wrapper type
+type wrapper struct {
+	valueField string
+	setField   string
+}
+
+// RegisterWrapper registers the struct type wrapping a single value, like `type OptionalString struct { Value string; Set bool }`,
+// so fields of the type are set by a single env variable or config key, e.g. `MYAPP_NAME=foo`.
+// The value is decoded into the field named valueField and the bool field named setField is set to true.
+// Maps with keys of both fields, e.g. `{"value": "foo", "set": true}` in config file, are decoded as is.
+func (e *Enviper) RegisterWrapper(t reflect.Type, valueField, setField string) *Enviper {
+	if e.wrappers == nil {
+		e.wrappers = map[reflect.Type]wrapper{}
+	}
+	e.wrappers[t] = wrapper{valueField: valueField, setField: setField}
+	return e
+}
+
+// wrappersHook wraps values of registered wrapper types to maps of their fields
+func (e *Enviper) wrappersHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	w, ok := e.wrappers[t]
+	if !ok || f.Kind() == reflect.Map {
+		return data, nil
+	}
+	valueKey, ok := e.fieldKey(t, w.valueField)
+	if !ok {
+		return data, nil
+	}
+	setKey, ok := e.fieldKey(t, w.setField)
+	if !ok {
+		return data, nil
+	}
+	return map[string]interface{}{valueKey: data, setKey: true}, nil
+}
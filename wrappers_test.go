@@ -0,0 +1,84 @@
+package enviper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iamolegga/enviper"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type OptionalString struct {
+	Value string
+	Set   bool
+}
+
+type OptionalInt struct {
+	Value int `mapstructure:"v"`
+	Set   bool
+}
+
+type WrappersConfig struct {
+	Name    OptionalString
+	Port    OptionalInt
+	Timeout *OptionalInt
+	Missing OptionalString
+	Nested  struct {
+		Level OptionalString
+	}
+}
+
+func wrappersEnviper() *enviper.Enviper {
+	e := enviper.New(viper.New()).
+		RegisterWrapper(reflect.TypeOf(OptionalString{}), "Value", "Set").
+		RegisterWrapper(reflect.TypeOf(OptionalInt{}), "Value", "Set")
+	e.SetEnvPrefix("APP")
+	return e
+}
+
+func TestWrappers(t *testing.T) {
+	dir, cleanup := writeConfig(t, `
+port: 80
+nested:
+  level:
+    value: debug
+    set: false
+`)
+	defer cleanup()
+	defer setenv(t, map[string]string{
+		"APP_NAME":    "app",
+		"APP_TIMEOUT": "30",
+	})()
+
+	e := wrappersEnviper()
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c WrappersConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, OptionalString{Value: "app", Set: true}, c.Name)
+		assert.Equal(t, OptionalInt{Value: 80, Set: true}, c.Port)
+		assert.Equal(t, &OptionalInt{Value: 30, Set: true}, c.Timeout)
+		assert.Equal(t, OptionalString{}, c.Missing)
+		assert.Equal(t, OptionalString{Value: "debug"}, c.Nested.Level)
+	}
+}
+
+func TestWrappersOverrideFile(t *testing.T) {
+	dir, cleanup := writeConfig(t, `port: 80`)
+	defer cleanup()
+	defer setenv(t, map[string]string{"APP_PORT": "8080"})()
+
+	e := wrappersEnviper()
+	e.AddConfigPath(dir)
+	e.SetConfigName("config")
+
+	var c WrappersConfig
+	if assert.Nil(t, e.Unmarshal(&c)) {
+		assert.Equal(t, OptionalInt{Value: 8080, Set: true}, c.Port)
+	}
+
+	defer setenv(t, map[string]string{"APP_PORT": "not a number"})()
+	assert.NotNil(t, e.Unmarshal(&c))
+}